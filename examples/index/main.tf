@@ -172,3 +172,27 @@ resource "mongodb_index" "test_partial_filter" {
     "status" : "active"
   })
 }
+
+resource "mongodb_index" "test_text" {
+  database   = "test"
+  collection = "test"
+  name       = "text"
+  keys = [
+    {
+      "field" : "category",
+      "type" : "asc"
+    },
+    {
+      "field" : "title",
+      "type" : "text"
+    },
+    {
+      "field" : "body",
+      "type" : "text"
+    }
+  ]
+  weights = {
+    "title" : 10
+  }
+  default_language = "french"
+}
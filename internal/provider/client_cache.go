@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"reflect"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// clients shares the MongoDB clients between the provider instances of the process, so
+// that the instances connecting to the same deployment use a single connection pool.
+var clients = newClientCache()
+
+// clientCache holds the MongoDB clients, keyed by the settings they were created with.
+type clientCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedClient
+}
+
+type cachedClient struct {
+	client *mongo.Client
+	// dialer is closed along with the client, when it holds a connection such as an SSH tunnel.
+	dialer io.Closer
+	// limiter queues the operations of the resources using the client.
+	limiter *operationLimiter
+	// references counts the provider instances using the client.
+	references int
+}
+
+func newClientCache() *clientCache {
+	return &clientCache{entries: map[string]*cachedClient{}}
+}
+
+// acquire returns the client created with the settings identified by key, creating it
+// with opts when there is none, along with the limiter of its operations. Each call must
+// be paired with a call to release.
+func (c *clientCache) acquire(key string, opts *options.ClientOptions, limits operationLimits) (*mongo.Client, *operationLimiter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		entry.references++
+		return entry.client, entry.limiter, nil
+	}
+
+	client, err := mongo.Connect(context.Background(), opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	entry := &cachedClient{client: client, limiter: newOperationLimiter(limits), references: 1}
+	if dialer, ok := opts.Dialer.(io.Closer); ok {
+		entry.dialer = dialer
+	}
+	c.entries[key] = entry
+	return client, entry.limiter, nil
+}
+
+// release gives up a client returned by acquire. The client is disconnected once it is
+// no longer used.
+func (c *clientCache) release(ctx context.Context, key string) error {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+		return nil
+	}
+	entry.references--
+	if entry.references > 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	delete(c.entries, key)
+	c.mu.Unlock()
+
+	return entry.disconnect(ctx)
+}
+
+// disconnectAll disconnects every client, whether it is still used or not.
+func (c *clientCache) disconnectAll(ctx context.Context) error {
+	c.mu.Lock()
+	entries := c.entries
+	c.entries = map[string]*cachedClient{}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, entry := range entries {
+		if err := entry.disconnect(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// disconnect disconnects the client, then closes its dialer.
+func (e *cachedClient) disconnect(ctx context.Context) error {
+	err := e.client.Disconnect(ctx)
+	if e.dialer != nil {
+		err = errors.Join(err, e.dialer.Close())
+	}
+	return err
+}
+
+// DisconnectClients disconnects the MongoDB clients created by the provider, and closes
+// their SSH tunnels. It is called when the provider process stops.
+func DisconnectClients(ctx context.Context) error {
+	return clients.disconnectAll(ctx)
+}
+
+// cacheKey identifies the effective settings of the client, so that the provider
+// instances with the same settings share a client.
+func (c *clientConfig) cacheKey() string {
+	h := sha256.New()
+	writeCacheKey(h, reflect.ValueOf(*c))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeCacheKey writes every field of value, following pointers, so that equal settings
+// give equal keys whatever their addresses.
+func writeCacheKey(h hash.Hash, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			fmt.Fprint(h, "nil;")
+			return
+		}
+		writeCacheKey(h, value.Elem())
+	case reflect.Struct:
+		fmt.Fprint(h, "{")
+		for i := 0; i < value.NumField(); i++ {
+			fmt.Fprint(h, value.Type().Field(i).Name, ":")
+			writeCacheKey(h, value.Field(i))
+		}
+		fmt.Fprint(h, "};")
+	case reflect.Slice:
+		fmt.Fprint(h, "[")
+		for i := 0; i < value.Len(); i++ {
+			writeCacheKey(h, value.Index(i))
+		}
+		fmt.Fprint(h, "];")
+	default:
+		fmt.Fprintf(h, "%q;", fmt.Sprint(value))
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestClientConfigCacheKey(t *testing.T) {
+	timeout, sameTimeout, otherTimeout := 5*time.Second, 5*time.Second, 10*time.Second
+	config := clientConfig{url: "mongodb://localhost", serverSelectionTimeout: &timeout, tls: &clientTlsConfig{serverName: "mongo"}}
+	same := clientConfig{url: "mongodb://localhost", serverSelectionTimeout: &sameTimeout, tls: &clientTlsConfig{serverName: "mongo"}}
+	other := clientConfig{url: "mongodb://localhost", serverSelectionTimeout: &otherTimeout, tls: &clientTlsConfig{serverName: "mongo"}}
+
+	if config.cacheKey() != same.cacheKey() {
+		t.Fatalf("Expected equal settings to give the same key")
+	}
+	if config.cacheKey() == other.cacheKey() {
+		t.Fatalf("Expected different settings to give different keys")
+	}
+}
+
+func TestClientCacheSharesClients(t *testing.T) {
+	cache := newClientCache()
+	opts := options.Client().ApplyURI("mongodb://localhost")
+
+	first, firstLimiter, err := cache.acquire("key", opts, operationLimits{total: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, secondLimiter, err := cache.acquire("key", opts, operationLimits{total: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != second {
+		t.Fatalf("Expected the client to be shared")
+	}
+	if firstLimiter != secondLimiter {
+		t.Fatalf("Expected the limiter to be shared")
+	}
+
+	if err := cache.release(context.Background(), "key"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := cache.entries["key"]; !ok {
+		t.Fatalf("Expected the client to be kept while it is used")
+	}
+
+	if err := cache.release(context.Background(), "key"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := cache.entries["key"]; ok {
+		t.Fatalf("Expected the client to be disconnected once it is no longer used")
+	}
+}
@@ -0,0 +1,556 @@
+package provider
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	authMechanismScramSha1   = "SCRAM-SHA-1"
+	authMechanismScramSha256 = "SCRAM-SHA-256"
+	authMechanismPlain       = "PLAIN"
+	authMechanismX509        = "MONGODB-X509"
+)
+
+// Authentication mechanisms that can be set with the auth_mechanism attribute.
+var supportedAuthMechanisms = []string{
+	authMechanismScramSha1,
+	authMechanismScramSha256,
+	authMechanismPlain,
+	authMechanismX509,
+}
+
+// clientConfig holds the connection settings of a MongoDB client, once resolved
+// from the provider configuration and the environment.
+type clientConfig struct {
+	url              string
+	hosts            []string
+	srvHost          string
+	replicaSet       string
+	directConnection *bool
+	appName          string
+	username         string
+	password         string
+	authSource       string
+	authMechanism    string
+	tls              *clientTlsConfig
+
+	// urlFromFile records that the url was read from the file of url_file or MONGODB_URL_FILE.
+	urlFromFile bool
+
+	// credentialCommand prints the username and password, which replace the ones above
+	// when the command is run.
+	credentialCommand []string
+
+	connectTimeout         *time.Duration
+	serverSelectionTimeout *time.Duration
+	socketTimeout          *time.Duration
+	maxPoolSize            *int64
+	minPoolSize            *int64
+	retryReads             *bool
+	retryWrites            *bool
+	compressors            []string
+
+	// maxConcurrentOperations limits the operations changing the deployment that run at
+	// the same time, in total and per collection.
+	maxConcurrentOperations              *int64
+	maxConcurrentOperationsPerCollection *int64
+
+	serverApi *clientServerApiConfig
+
+	sshTunnel *clientSshTunnelConfig
+	proxy     *clientProxyConfig
+}
+
+// clientServerApiConfig holds the Stable API settings of a MongoDB client.
+type clientServerApiConfig struct {
+	disabled          bool
+	version           string
+	strict            *bool
+	deprecationErrors *bool
+}
+
+// Compressors that can be set with the compressors attribute.
+var supportedCompressors = []string{"zstd", "snappy", "zlib"}
+
+// clientOptions builds the options used to create the MongoDB client.
+func (c *clientConfig) clientOptions() (*options.ClientOptions, diag.Diagnostics) {
+	opts, diags := c.deploymentOptions()
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	credential, credentialDiags := c.credential(opts.Auth)
+	diags.Append(credentialDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if credential != nil {
+		opts.SetAuth(*credential)
+	}
+
+	if c.tls != nil {
+		tlsConfig, tlsDiags := c.tls.tlsConfig()
+		diags.Append(tlsDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	diags.Append(c.applyTuningOptions(opts)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if opts.Auth != nil && strings.EqualFold(opts.Auth.AuthMechanism, authMechanismX509) {
+		diags.Append(c.completeX509Credential(opts)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	if c.sshTunnel != nil {
+		tunnel, tunnelDiags := c.sshTunnel.dialer()
+		diags.Append(tunnelDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+		opts.SetDialer(tunnel)
+	}
+
+	if c.proxy != nil {
+		dialer, err := c.proxy.dialer()
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("proxy"),
+				"Invalid MongoDB Proxy Settings",
+				"Error: "+err.Error(),
+			)
+			return nil, diags
+		}
+		opts.SetDialer(dialer)
+	}
+
+	serverApiOptions, serverApiDiags := c.serverApiOptions()
+	diags.Append(serverApiDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if serverApiOptions != nil {
+		opts.SetServerAPIOptions(serverApiOptions)
+	}
+
+	return opts, diags
+}
+
+// serverApiOptions returns the Stable API options, nil when it is disabled. The Stable
+// API version 1 is used by default.
+func (c *clientConfig) serverApiOptions() (*options.ServerAPIOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if c.serverApi == nil {
+		return options.ServerAPI(options.ServerAPIVersion1), diags
+	}
+
+	if c.serverApi.disabled {
+		if c.serverApi.version != "" || c.serverApi.strict != nil || c.serverApi.deprecationErrors != nil {
+			diags.AddAttributeError(
+				path.Root("server_api").AtName("enabled"),
+				"Conflicting MongoDB Stable API Settings",
+				"The version, strict and deprecation_errors settings cannot be used when the Stable API is disabled.",
+			)
+		}
+		return nil, diags
+	}
+
+	version := options.ServerAPIVersion1
+	if c.serverApi.version != "" {
+		version = options.ServerAPIVersion(c.serverApi.version)
+	}
+	if err := version.Validate(); err != nil {
+		diags.AddAttributeError(
+			path.Root("server_api").AtName("version"),
+			"Unsupported MongoDB Stable API Version",
+			"Error: "+err.Error(),
+		)
+		return nil, diags
+	}
+
+	serverApiOptions := options.ServerAPI(version)
+	if c.serverApi.strict != nil {
+		serverApiOptions.SetStrict(*c.serverApi.strict)
+	}
+	if c.serverApi.deprecationErrors != nil {
+		serverApiOptions.SetDeprecationErrors(*c.serverApi.deprecationErrors)
+	}
+
+	return serverApiOptions, diags
+}
+
+// deploymentOptions builds the options locating the deployment, from exactly one of
+// the url, the hosts and the srv_host attributes. The replica_set, direct_connection
+// and app_name attributes take precedence over the options of the url.
+func (c *clientConfig) deploymentOptions() (*options.ClientOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if c.url != "" && len(c.hosts) > 0 {
+		diags.AddAttributeError(
+			path.Root("hosts"),
+			"Conflicting MongoDB Hosts",
+			"The hosts attribute cannot be used together with the url. Either remove the hosts attribute or the url.",
+		)
+	}
+	if c.url != "" && c.srvHost != "" {
+		diags.AddAttributeError(
+			path.Root("srv_host"),
+			"Conflicting MongoDB SRV Host",
+			"The srv_host attribute cannot be used together with the url. Either remove the srv_host attribute or the url.",
+		)
+	}
+	if len(c.hosts) > 0 && c.srvHost != "" {
+		diags.AddAttributeError(
+			path.Root("srv_host"),
+			"Conflicting MongoDB SRV Host",
+			"The srv_host attribute cannot be used together with hosts, the hosts are resolved from the SRV record. "+
+				"Either remove the srv_host attribute or the hosts attribute.",
+		)
+	}
+	if c.srvHost != "" && c.directConnection != nil && *c.directConnection {
+		diags.AddAttributeError(
+			path.Root("direct_connection"),
+			"Invalid MongoDB Direct Connection",
+			"A direct connection cannot be made to hosts resolved from an SRV record.",
+		)
+	}
+	for i, host := range c.hosts {
+		if strings.TrimSpace(host) == "" {
+			diags.AddAttributeError(
+				path.Root("hosts").AtListIndex(i),
+				"Invalid MongoDB Host",
+				"Hosts must not be empty.",
+			)
+		}
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	opts := options.Client()
+	attributePath := c.deploymentPath()
+	switch {
+	case c.srvHost != "":
+		opts.ApplyURI("mongodb+srv://" + c.srvHost)
+	case len(c.hosts) > 0:
+		opts.SetHosts(c.hosts)
+	default:
+		opts.ApplyURI(c.url)
+	}
+
+	if c.replicaSet != "" {
+		opts.SetReplicaSet(c.replicaSet)
+	}
+	if c.directConnection != nil {
+		opts.SetDirect(*c.directConnection)
+	}
+	if c.appName != "" {
+		opts.SetAppName(c.appName)
+	}
+
+	if opts.Direct != nil && *opts.Direct && len(opts.Hosts) > 1 {
+		diags.AddAttributeError(
+			path.Root("direct_connection"),
+			"Invalid MongoDB Direct Connection",
+			fmt.Sprintf("A direct connection cannot be made as %d hosts are specified. ", len(opts.Hosts))+
+				"Either keep a single host or disable direct_connection.",
+		)
+		return nil, diags
+	}
+
+	if err := opts.Validate(); err != nil {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid MongoDB Connection Settings",
+			"The provider cannot create the MongoDB client from the connection settings.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return nil, diags
+	}
+
+	return opts, diags
+}
+
+// applyTuningOptions applies the timeouts, pool sizes, retry settings and compressors
+// on top of the options of the url.
+func (c *clientConfig) applyTuningOptions(opts *options.ClientOptions) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if c.connectTimeout != nil {
+		opts.SetConnectTimeout(*c.connectTimeout)
+	}
+	if c.serverSelectionTimeout != nil {
+		opts.SetServerSelectionTimeout(*c.serverSelectionTimeout)
+	}
+	if c.socketTimeout != nil {
+		opts.SetSocketTimeout(*c.socketTimeout)
+	}
+
+	if c.maxPoolSize != nil {
+		if *c.maxPoolSize < 0 {
+			diags.AddAttributeError(path.Root("max_pool_size"), "Invalid MongoDB Max Pool Size", "The max_pool_size must not be negative.")
+		} else {
+			opts.SetMaxPoolSize(uint64(*c.maxPoolSize))
+		}
+	}
+	if c.minPoolSize != nil {
+		if *c.minPoolSize < 0 {
+			diags.AddAttributeError(path.Root("min_pool_size"), "Invalid MongoDB Min Pool Size", "The min_pool_size must not be negative.")
+		} else {
+			opts.SetMinPoolSize(uint64(*c.minPoolSize))
+		}
+	}
+	if opts.MaxPoolSize != nil && opts.MinPoolSize != nil && *opts.MaxPoolSize != 0 && *opts.MinPoolSize > *opts.MaxPoolSize {
+		diags.AddAttributeError(
+			path.Root("min_pool_size"),
+			"Invalid MongoDB Min Pool Size",
+			fmt.Sprintf("The min_pool_size (%d) must be less than or equal to the max_pool_size (%d).", *opts.MinPoolSize, *opts.MaxPoolSize),
+		)
+	}
+
+	if c.maxConcurrentOperations != nil && *c.maxConcurrentOperations < 1 {
+		diags.AddAttributeError(
+			path.Root("max_concurrent_operations"),
+			"Invalid MongoDB Max Concurrent Operations",
+			"The max_concurrent_operations must be at least 1.",
+		)
+	}
+	if c.maxConcurrentOperationsPerCollection != nil && *c.maxConcurrentOperationsPerCollection < 1 {
+		diags.AddAttributeError(
+			path.Root("max_concurrent_operations_per_collection"),
+			"Invalid MongoDB Max Concurrent Operations Per Collection",
+			"The max_concurrent_operations_per_collection must be at least 1.",
+		)
+	}
+
+	if c.retryReads != nil {
+		opts.SetRetryReads(*c.retryReads)
+	}
+	if c.retryWrites != nil {
+		opts.SetRetryWrites(*c.retryWrites)
+	}
+
+	if len(c.compressors) > 0 {
+		for i, compressor := range c.compressors {
+			if !slices.Contains(supportedCompressors, compressor) {
+				diags.AddAttributeError(
+					path.Root("compressors").AtListIndex(i),
+					"Unsupported MongoDB Compressor",
+					fmt.Sprintf("The compressor %q is not supported. Supported compressors are: %s.",
+						compressor, strings.Join(supportedCompressors, ", ")),
+				)
+			}
+		}
+		opts.SetCompressors(c.compressors)
+	}
+
+	return diags
+}
+
+// operationLimits returns the limits of the operations changing the deployment.
+func (c *clientConfig) operationLimits() operationLimits {
+	var limits operationLimits
+	if c.maxConcurrentOperations != nil {
+		limits.total = *c.maxConcurrentOperations
+	}
+	if c.maxConcurrentOperationsPerCollection != nil {
+		limits.perCollection = *c.maxConcurrentOperationsPerCollection
+	}
+	return limits
+}
+
+// configuredAuthMechanism returns the authentication mechanism set by the auth_mechanism
+// attribute or by the url, without resolving the url.
+func (c *clientConfig) configuredAuthMechanism() string {
+	if c.authMechanism != "" || c.url == "" || c.srvHost != "" || len(c.hosts) > 0 {
+		return c.authMechanism
+	}
+	parsed, err := url.Parse(c.url)
+	if err != nil {
+		return ""
+	}
+	return parsed.Query().Get("authMechanism")
+}
+
+// credential merges the authentication attributes with the credential parsed from
+// the url. An attribute may complete the url but never silently contradict it.
+func (c *clientConfig) credential(fromUrl *options.Credential) (*options.Credential, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if fromUrl == nil && c.username == "" && c.password == "" && c.authSource == "" && c.authMechanism == "" {
+		return nil, diags
+	}
+
+	credential := options.Credential{}
+	if fromUrl != nil {
+		credential = *fromUrl
+	}
+
+	if c.username != "" {
+		if credential.Username != "" && credential.Username != c.username {
+			diags.AddAttributeError(
+				path.Root("username"),
+				"Conflicting MongoDB Username",
+				"The url already contains a different username. "+
+					"Either remove the credentials from the url or remove the username attribute.",
+			)
+		}
+		credential.Username = c.username
+	}
+
+	if c.password != "" {
+		if credential.PasswordSet && credential.Password != c.password {
+			diags.AddAttributeError(
+				path.Root("password"),
+				"Conflicting MongoDB Password",
+				"The url already contains a different password. "+
+					"Either remove the credentials from the url or remove the password attribute.",
+			)
+		}
+		credential.Password = c.password
+		credential.PasswordSet = true
+	}
+
+	if c.authMechanism != "" {
+		if !isSupportedAuthMechanism(c.authMechanism) {
+			diags.AddAttributeError(
+				path.Root("auth_mechanism"),
+				"Unsupported MongoDB Authentication Mechanism",
+				fmt.Sprintf("The authentication mechanism %q is not supported. Supported mechanisms are: %s.",
+					c.authMechanism, strings.Join(supportedAuthMechanisms, ", ")),
+			)
+		}
+		if credential.AuthMechanism != "" && !strings.EqualFold(credential.AuthMechanism, c.authMechanism) {
+			diags.AddAttributeError(
+				path.Root("auth_mechanism"),
+				"Conflicting MongoDB Authentication Mechanism",
+				fmt.Sprintf("The url sets the authentication mechanism %q but the auth_mechanism attribute is %q. "+
+					"Either remove authMechanism from the url or remove the auth_mechanism attribute.",
+					credential.AuthMechanism, c.authMechanism),
+			)
+		}
+		credential.AuthMechanism = c.authMechanism
+	}
+
+	// The auth_source attribute takes precedence as the url parser fills in a default
+	// auth source whenever credentials are present.
+	if c.authSource != "" {
+		credential.AuthSource = c.authSource
+	}
+
+	switch strings.ToUpper(credential.AuthMechanism) {
+	case authMechanismX509:
+		if credential.PasswordSet {
+			diags.AddAttributeError(
+				path.Root("password"),
+				"Conflicting MongoDB Password",
+				"A password cannot be used with the MONGODB-X509 authentication mechanism, "+
+					"the client certificate is used to authenticate.",
+			)
+		}
+	case authMechanismPlain:
+		if credential.AuthSource == "" {
+			credential.AuthSource = "$external"
+		}
+		fallthrough
+	default:
+		if credential.Username == "" && credential.PasswordSet {
+			diags.AddAttributeError(
+				path.Root("username"),
+				"Missing MongoDB Username",
+				"A password is set but there is no username to authenticate with. "+
+					"Set the username attribute, the MONGODB_USERNAME environment variable or add the username to the url.",
+			)
+		}
+		if credential.Username == "" && credential.AuthMechanism != "" {
+			diags.AddAttributeError(
+				path.Root("username"),
+				"Missing MongoDB Username",
+				fmt.Sprintf("The %s authentication mechanism requires a username. ", credential.AuthMechanism)+
+					"Set the username attribute, the MONGODB_USERNAME environment variable or add the username to the url.",
+			)
+		}
+	}
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	// An auth source alone does not enable authentication.
+	if credential.Username == "" && credential.AuthMechanism == "" {
+		return fromUrl, diags
+	}
+
+	return &credential, diags
+}
+
+// completeX509Credential completes the credential of the MONGODB-X509 mechanism
+// from the client certificate: the user is named after the subject of the
+// certificate and is defined in the $external database.
+func (c *clientConfig) completeX509Credential(opts *options.ClientOptions) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if c.authSource != "" && c.authSource != "$external" {
+		diags.AddAttributeError(
+			path.Root("auth_source"),
+			"Invalid MongoDB Auth Source",
+			"Users authenticated with the MONGODB-X509 mechanism are defined in the $external database. "+
+				"Remove the auth_source attribute or set it to $external.",
+		)
+		return diags
+	}
+	opts.Auth.AuthSource = "$external"
+
+	if opts.TLSConfig == nil || len(opts.TLSConfig.Certificates) == 0 {
+		diags.AddAttributeError(
+			path.Root("tls"),
+			"Missing Client Certificate",
+			"The MONGODB-X509 authentication mechanism requires a client certificate. "+
+				"Set client_certificate_pem or client_certificate_file in the tls configuration.",
+		)
+		return diags
+	}
+
+	if opts.Auth.Username == "" {
+		certificate, err := x509.ParseCertificate(opts.TLSConfig.Certificates[0].Certificate[0])
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("tls"),
+				"Invalid Client Certificate",
+				"The subject of the client certificate could not be read.\n\n"+
+					"Error: "+err.Error(),
+			)
+			return diags
+		}
+		opts.Auth.Username = certificate.Subject.String()
+	}
+
+	return diags
+}
+
+func isSupportedAuthMechanism(mechanism string) bool {
+	for _, supported := range supportedAuthMechanisms {
+		if strings.EqualFold(supported, mechanism) {
+			return true
+		}
+	}
+	return false
+}
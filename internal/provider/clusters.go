@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// mongodbProviderClusterModel maps a named cluster of the clusters attribute.
+type mongodbProviderClusterModel struct {
+	Url           types.String `tfsdk:"url"`
+	Username      types.String `tfsdk:"username"`
+	Password      types.String `tfsdk:"password"`
+	AuthSource    types.String `tfsdk:"auth_source"`
+	AuthMechanism types.String `tfsdk:"auth_mechanism"`
+}
+
+// Attributes of the provider that are set per cluster in the clusters attribute.
+var clusterAttributes = []string{"url", "username", "password", "auth_source", "auth_mechanism"}
+
+// mongodbProviderClusters is made available to the resources of the provider. It holds
+// the provider data of the deployment set by the provider attributes, if any, and of
+// each named cluster.
+type mongodbProviderClusters struct {
+	defaultCluster *mongodbProviderData
+	named          map[string]*mongodbProviderData
+
+	// readOnly forbids the operations writing to the clusters.
+	readOnly bool
+	// preview records the commands writing to the clusters instead of running them, when set.
+	preview *commandPreview
+	// maintenance refuses the operations writing to the clusters outside of the maintenance windows, when set.
+	maintenance *maintenanceSchedule
+}
+
+// cluster returns the provider data of the named cluster, or of the deployment set by
+// the provider attributes when name is empty.
+func (c *mongodbProviderClusters) cluster(name string) (*mongodbProviderData, error) {
+	if name == "" {
+		if c.defaultCluster == nil {
+			return nil, errors.New("the provider only has named clusters, set the cluster to one of: " + c.names())
+		}
+		return c.defaultCluster, nil
+	}
+
+	data, ok := c.named[name]
+	if !ok {
+		if len(c.named) == 0 {
+			return nil, fmt.Errorf("unknown cluster %s, the provider has no clusters attribute", name)
+		}
+		return nil, fmt.Errorf("unknown cluster %s, expected one of: %s", name, c.names())
+	}
+	return data, nil
+}
+
+// resourceCluster returns the provider data of the cluster set by the cluster attribute of a resource.
+func (c *mongodbProviderClusters) resourceCluster(name *string) (*mongodbProviderData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	data, err := c.cluster(stringValue(name))
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("cluster"),
+			"Unknown MongoDB Cluster",
+			"The cluster must be one of the clusters attribute of the provider, "+
+				"or unset to use the deployment set by the url of the provider.\n\n"+
+				"Error: "+err.Error(),
+		)
+	}
+	return data, diags
+}
+
+// checkWritable reports an error when the provider is read-only, before the operation
+// of the resource sends any command.
+func (c *mongodbProviderClusters) checkWritable(operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if c.readOnly {
+		diags.AddError(
+			"MongoDB Provider Is Read-Only",
+			"The provider refuses to "+operation+" resources as its read_only attribute or the MONGODB_READ_ONLY environment variable is set to true. "+
+				"Nothing was sent to the deployment.",
+		)
+	}
+	return diags
+}
+
+func (c *mongodbProviderClusters) names() string {
+	names := make([]string, 0, len(c.named))
+	for name := range c.named {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// release gives up the clients of every cluster.
+func (c *mongodbProviderClusters) release(ctx context.Context) error {
+	var errs []error
+	if c.defaultCluster != nil {
+		errs = append(errs, c.defaultCluster.release(ctx))
+	}
+	for _, data := range c.named {
+		errs = append(errs, data.release(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// clusterConfig returns the settings of a named cluster: its url and credentials, and
+// the other settings of the provider. The credentials of the provider are used unless
+// the cluster sets a username.
+func clusterConfig(base clientConfig, cluster mongodbProviderClusterModel) clientConfig {
+	config := base
+	config.url = cluster.Url.ValueString()
+	config.urlFromFile = false
+	config.hosts = nil
+	config.srvHost = ""
+	config.replicaSet = ""
+
+	if !cluster.Username.IsNull() {
+		config.username = cluster.Username.ValueString()
+		config.password = cluster.Password.ValueString()
+		config.credentialCommand = nil
+	}
+	if !cluster.AuthSource.IsNull() {
+		config.authSource = cluster.AuthSource.ValueString()
+	}
+	if !cluster.AuthMechanism.IsNull() {
+		config.authMechanism = cluster.AuthMechanism.ValueString()
+	}
+	return config
+}
+
+// clusters returns the named clusters of the configuration, reporting the values that
+// are not known yet in unknown.
+func (m *mongodbProviderModel) clusters(ctx context.Context, unknown *diag.Diagnostics) (map[string]mongodbProviderClusterModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if m.Clusters.IsNull() || m.Clusters.IsUnknown() {
+		return nil, diags
+	}
+
+	known := true
+	for name, value := range m.Clusters.Elements() {
+		if value.IsUnknown() {
+			addUnknownAttributeError(unknown, path.Root("clusters").AtMapKey(name), "Cluster Configuration", "")
+			known = false
+		}
+	}
+	if !known {
+		return nil, diags
+	}
+
+	clusters := map[string]mongodbProviderClusterModel{}
+	diags.Append(m.Clusters.ElementsAs(ctx, &clusters, false)...)
+	for name, cluster := range clusters {
+		addUnknownNestedAttributeErrors(unknown, path.Root("clusters").AtMapKey(name), "Cluster Configuration", []nestedAttributeValue{
+			{"url", cluster.Url},
+			{"username", cluster.Username},
+			{"password", cluster.Password},
+			{"auth_source", cluster.AuthSource},
+			{"auth_mechanism", cluster.AuthMechanism},
+		})
+	}
+	return clusters, diags
+}
+
+// clusterDiagnostics moves the errors about the settings of a named cluster to the
+// matching attribute of the clusters attribute, and names the cluster in the others.
+func clusterDiagnostics(name string, diags diag.Diagnostics) diag.Diagnostics {
+	if !diags.HasError() {
+		return diags
+	}
+
+	clusterPath := path.Root("clusters").AtMapKey(name)
+	moved := make(diag.Diagnostics, 0, len(diags))
+	for _, d := range diags {
+		if d.Severity() != diag.SeverityError {
+			moved = append(moved, d)
+			continue
+		}
+		detail := d.Detail() + "\n\nThe error concerns the " + name + " cluster."
+		withPath, ok := d.(diag.DiagnosticWithPath)
+		if !ok {
+			moved = append(moved, diag.NewErrorDiagnostic(d.Summary(), detail))
+			continue
+		}
+
+		attributePath := withPath.Path()
+		if steps := attributePath.Steps(); len(steps) == 1 {
+			if attributeName, ok := steps[0].(path.PathStepAttributeName); ok && slices.Contains(clusterAttributes, string(attributeName)) {
+				attributePath = clusterPath.AtName(string(attributeName))
+			}
+		}
+		moved = append(moved, diag.NewAttributeErrorDiagnostic(attributePath, d.Summary(), detail))
+	}
+	return moved
+}
+
+func clusterResourceAttribute() resourceschema.StringAttribute {
+	return resourceschema.StringAttribute{
+		Optional: true,
+		Description: "Name of the cluster of the clusters attribute of the provider where to manage the resource. " +
+			"Defaults to the deployment set by the url of the provider.",
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// clusterObjectType is the type of the elements of the clusters attribute.
+var clusterObjectType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"url":            types.StringType,
+	"username":       types.StringType,
+	"password":       types.StringType,
+	"auth_source":    types.StringType,
+	"auth_mechanism": types.StringType,
+}}
+
+func TestClusterLookup(t *testing.T) {
+	eu := &mongodbProviderData{}
+	clusters := &mongodbProviderClusters{named: map[string]*mongodbProviderData{"eu": eu, "us": {}}}
+
+	data, err := clusters.cluster("eu")
+	if err != nil || data != eu {
+		t.Fatalf("Expected the eu cluster, got %v, err %v", data, err)
+	}
+
+	_, err = clusters.cluster("asia")
+	if err == nil || !strings.Contains(err.Error(), "eu, us") {
+		t.Fatalf("Expected the clusters to be listed, got %v", err)
+	}
+
+	_, err = clusters.cluster("")
+	if err == nil {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestResourceClusterDefault(t *testing.T) {
+	defaultCluster := &mongodbProviderData{}
+	clusters := &mongodbProviderClusters{defaultCluster: defaultCluster}
+
+	data, diags := clusters.resourceCluster(nil)
+	if diags.HasError() || data != defaultCluster {
+		t.Fatalf("Expected the default cluster, got %v, diags %v", data, diags)
+	}
+
+	name := "eu"
+	_, diags = clusters.resourceCluster(&name)
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestClusterConfig(t *testing.T) {
+	base := clientConfig{
+		url:               "mongodb://default",
+		replicaSet:        "rs0",
+		username:          "provider",
+		password:          "secret",
+		authSource:        "admin",
+		credentialCommand: []string{"credentials"},
+		appName:           "terraform",
+	}
+
+	config := clusterConfig(base, mongodbProviderClusterModel{
+		Url:           types.StringValue("mongodb://eu"),
+		Username:      types.StringNull(),
+		Password:      types.StringNull(),
+		AuthSource:    types.StringNull(),
+		AuthMechanism: types.StringNull(),
+	})
+	if config.url != "mongodb://eu" || config.replicaSet != "" || config.appName != "terraform" {
+		t.Fatalf("Unexpected settings %+v", config)
+	}
+	if config.username != "provider" || len(config.credentialCommand) != 1 {
+		t.Fatalf("Expected the credentials of the provider, got %+v", config)
+	}
+
+	config = clusterConfig(base, mongodbProviderClusterModel{
+		Url:           types.StringValue("mongodb://eu"),
+		Username:      types.StringValue("eu"),
+		Password:      types.StringValue("eu-secret"),
+		AuthSource:    types.StringNull(),
+		AuthMechanism: types.StringNull(),
+	})
+	if config.username != "eu" || config.password != "eu-secret" || config.authSource != "admin" || config.credentialCommand != nil {
+		t.Fatalf("Expected the credentials of the cluster, got %+v", config)
+	}
+}
+
+func TestClusterDiagnostics(t *testing.T) {
+	var diags diag.Diagnostics
+	diags.AddAttributeError(path.Root("url"), "Invalid MongoDB Url", "Error")
+	diags.AddAttributeError(path.Root("tls").AtName("ca_certificate_file"), "Invalid CA Certificate", "Error")
+
+	moved := clusterDiagnostics("eu", diags)
+
+	expected := path.Root("clusters").AtMapKey("eu").AtName("url")
+	withPath, ok := moved[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(expected) {
+		t.Fatalf("Expected %v, got %v", expected, moved[0])
+	}
+	withPath, ok = moved[1].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("tls").AtName("ca_certificate_file")) {
+		t.Fatalf("Expected the tls path to be kept, got %v", moved[1])
+	}
+	if !strings.Contains(moved[1].Detail(), "eu cluster") {
+		t.Fatalf("Expected the cluster to be named, got %v", moved[1].Detail())
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	clusters := &mongodbProviderClusters{}
+	if diags := clusters.checkWritable("create"); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	clusters.readOnly = true
+	diags := clusters.checkWritable("create")
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "refuses to create") {
+		t.Fatalf("Expected the operation to be refused, got %v", diags)
+	}
+}
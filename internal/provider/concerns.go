@@ -0,0 +1,290 @@
+package provider
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// Read concern levels that can be set with the read_concern attribute.
+var supportedReadConcernLevels = []string{"local", "available", "majority", "linearizable", "snapshot"}
+
+// Read preference modes that can be set with the read_preference attribute.
+var supportedReadPreferenceModes = []string{"primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest"}
+
+const (
+	writeConcernDescription               = "Write concern of the commands sent to the server."
+	writeConcernWDescription              = "Number of members that must acknowledge the write, \"majority\" or the name of a custom write concern."
+	writeConcernJDescription              = "Whether the write must be written to the on-disk journal before being acknowledged."
+	writeConcernWTimeoutDescription       = "Time limit of the write concern, such as 30s."
+	readConcernDescription                = "Read concern of the commands sent to the server."
+	readConcernLevelDescription           = "Level of the read concern, one of local, available, majority, linearizable or snapshot."
+	readPreferenceDescription             = "Read preference of the commands sent to the server."
+	readPreferenceModeDescription         = "Mode of the read preference, one of primary, primaryPreferred, secondary, secondaryPreferred or nearest."
+	readPreferenceMaxStalenessDescription = "Maximum replication lag of the secondaries that can be read from, such as 90s."
+	concernsOverrideDescription           = " Overrides the one of the provider."
+	concernsProviderDescription           = " Applies to every database handle opened by the resources, unless overridden by the resource."
+)
+
+type writeConcernModel struct {
+	W        types.String `tfsdk:"w"`
+	J        types.Bool   `tfsdk:"j"`
+	WTimeout types.String `tfsdk:"wtimeout"`
+}
+
+type readConcernModel struct {
+	Level types.String `tfsdk:"level"`
+}
+
+type readPreferenceModel struct {
+	Mode         types.String `tfsdk:"mode"`
+	MaxStaleness types.String `tfsdk:"max_staleness"`
+}
+
+// operationConcerns holds the write concern, read concern and read preference applied
+// to the database handles. Nil values fall back to the ones of the client.
+type operationConcerns struct {
+	writeConcern   *writeconcern.WriteConcern
+	readConcern    *readconcern.ReadConcern
+	readPreference *readpref.ReadPref
+}
+
+// newOperationConcerns converts the concerns attributes found under attributePath.
+func newOperationConcerns(attributePath path.Path, wc *writeConcernModel, rc *readConcernModel, rp *readPreferenceModel) (operationConcerns, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var concerns operationConcerns
+
+	if wc != nil {
+		concerns.writeConcern = &writeconcern.WriteConcern{}
+		if w := wc.W.ValueString(); w != "" {
+			if number, err := strconv.Atoi(w); err == nil {
+				concerns.writeConcern.W = number
+			} else {
+				concerns.writeConcern.W = w
+			}
+		}
+		if !wc.J.IsNull() {
+			concerns.writeConcern.Journal = wc.J.ValueBoolPointer()
+		}
+		if wtimeout := wc.WTimeout.ValueString(); wtimeout != "" {
+			duration, err := parseDuration(wtimeout)
+			if err != nil {
+				diags.AddAttributeError(
+					attributePath.AtName("write_concern").AtName("wtimeout"),
+					"Invalid Write Concern Timeout",
+					"The wtimeout must be a positive duration such as 30s.\n\n"+
+						"Error: "+err.Error(),
+				)
+			}
+			concerns.writeConcern.WTimeout = duration
+		}
+	}
+
+	if rc != nil && rc.Level.ValueString() != "" {
+		concerns.readConcern = &readconcern.ReadConcern{Level: rc.Level.ValueString()}
+	}
+
+	if rp != nil && rp.Mode.ValueString() != "" {
+		mode, err := readpref.ModeFromString(rp.Mode.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				attributePath.AtName("read_preference").AtName("mode"),
+				"Invalid Read Preference Mode",
+				"Error: "+err.Error(),
+			)
+			return concerns, diags
+		}
+
+		var readPrefOptions []readpref.Option
+		if maxStaleness := rp.MaxStaleness.ValueString(); maxStaleness != "" {
+			duration, err := parseDuration(maxStaleness)
+			if err != nil {
+				diags.AddAttributeError(
+					attributePath.AtName("read_preference").AtName("max_staleness"),
+					"Invalid Read Preference Max Staleness",
+					"The max_staleness must be a positive duration such as 90s.\n\n"+
+						"Error: "+err.Error(),
+				)
+				return concerns, diags
+			}
+			readPrefOptions = append(readPrefOptions, readpref.WithMaxStaleness(duration))
+		}
+
+		concerns.readPreference, err = readpref.New(mode, readPrefOptions...)
+		if err != nil {
+			diags.AddAttributeError(
+				attributePath.AtName("read_preference"),
+				"Invalid Read Preference",
+				"Error: "+err.Error(),
+			)
+		}
+	}
+
+	return concerns, diags
+}
+
+// override returns the concerns, replaced by the ones set in overrides.
+func (c operationConcerns) override(overrides operationConcerns) operationConcerns {
+	if overrides.writeConcern != nil {
+		c.writeConcern = overrides.writeConcern
+	}
+	if overrides.readConcern != nil {
+		c.readConcern = overrides.readConcern
+	}
+	if overrides.readPreference != nil {
+		c.readPreference = overrides.readPreference
+	}
+	return c
+}
+
+// databaseOptions returns the options of a database handle using the concerns.
+func (c operationConcerns) databaseOptions() *options.DatabaseOptions {
+	opts := options.Database()
+	if c.writeConcern != nil {
+		opts.SetWriteConcern(c.writeConcern)
+	}
+	if c.readConcern != nil {
+		opts.SetReadConcern(c.readConcern)
+	}
+	if c.readPreference != nil {
+		opts.SetReadPreference(c.readPreference)
+	}
+	return opts
+}
+
+func writeConcernProviderAttribute() providerschema.SingleNestedAttribute {
+	return providerschema.SingleNestedAttribute{
+		Optional:    true,
+		Description: writeConcernDescription + concernsProviderDescription,
+		Attributes: map[string]providerschema.Attribute{
+			"w": providerschema.StringAttribute{
+				Optional:    true,
+				Description: writeConcernWDescription,
+			},
+			"j": providerschema.BoolAttribute{
+				Optional:    true,
+				Description: writeConcernJDescription,
+			},
+			"wtimeout": providerschema.StringAttribute{
+				Optional:    true,
+				Description: writeConcernWTimeoutDescription,
+				Validators: []validator.String{
+					isDuration(),
+				},
+			},
+		},
+	}
+}
+
+func readConcernProviderAttribute() providerschema.SingleNestedAttribute {
+	return providerschema.SingleNestedAttribute{
+		Optional:    true,
+		Description: readConcernDescription + concernsProviderDescription,
+		Attributes: map[string]providerschema.Attribute{
+			"level": providerschema.StringAttribute{
+				Required:    true,
+				Description: readConcernLevelDescription,
+				Validators: []validator.String{
+					stringvalidator.OneOf(supportedReadConcernLevels...),
+				},
+			},
+		},
+	}
+}
+
+func readPreferenceProviderAttribute() providerschema.SingleNestedAttribute {
+	return providerschema.SingleNestedAttribute{
+		Optional:    true,
+		Description: readPreferenceDescription + concernsProviderDescription,
+		Attributes: map[string]providerschema.Attribute{
+			"mode": providerschema.StringAttribute{
+				Required:    true,
+				Description: readPreferenceModeDescription,
+				Validators: []validator.String{
+					stringvalidator.OneOf(supportedReadPreferenceModes...),
+				},
+			},
+			"max_staleness": providerschema.StringAttribute{
+				Optional:    true,
+				Description: readPreferenceMaxStalenessDescription,
+				Validators: []validator.String{
+					isDuration(),
+				},
+			},
+		},
+	}
+}
+
+func writeConcernResourceAttribute() resourceschema.SingleNestedAttribute {
+	return resourceschema.SingleNestedAttribute{
+		Optional:    true,
+		Description: writeConcernDescription + concernsOverrideDescription,
+		Attributes: map[string]resourceschema.Attribute{
+			"w": resourceschema.StringAttribute{
+				Optional:    true,
+				Description: writeConcernWDescription,
+			},
+			"j": resourceschema.BoolAttribute{
+				Optional:    true,
+				Description: writeConcernJDescription,
+			},
+			"wtimeout": resourceschema.StringAttribute{
+				Optional:    true,
+				Description: writeConcernWTimeoutDescription,
+				Validators: []validator.String{
+					isDuration(),
+				},
+			},
+		},
+	}
+}
+
+func readConcernResourceAttribute() resourceschema.SingleNestedAttribute {
+	return resourceschema.SingleNestedAttribute{
+		Optional:    true,
+		Description: readConcernDescription + concernsOverrideDescription,
+		Attributes: map[string]resourceschema.Attribute{
+			"level": resourceschema.StringAttribute{
+				Required:    true,
+				Description: readConcernLevelDescription,
+				Validators: []validator.String{
+					stringvalidator.OneOf(supportedReadConcernLevels...),
+				},
+			},
+		},
+	}
+}
+
+func readPreferenceResourceAttribute() resourceschema.SingleNestedAttribute {
+	return resourceschema.SingleNestedAttribute{
+		Optional:    true,
+		Description: readPreferenceDescription + concernsOverrideDescription,
+		Attributes: map[string]resourceschema.Attribute{
+			"mode": resourceschema.StringAttribute{
+				Required:    true,
+				Description: readPreferenceModeDescription,
+				Validators: []validator.String{
+					stringvalidator.OneOf(supportedReadPreferenceModes...),
+				},
+			},
+			"max_staleness": resourceschema.StringAttribute{
+				Optional:    true,
+				Description: readPreferenceMaxStalenessDescription,
+				Validators: []validator.String{
+					isDuration(),
+				},
+			},
+		},
+	}
+}
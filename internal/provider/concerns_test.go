@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func TestNewOperationConcerns(t *testing.T) {
+	concerns, diags := newOperationConcerns(
+		path.Empty(),
+		&writeConcernModel{W: types.StringValue("majority"), J: types.BoolValue(true), WTimeout: types.StringValue("30s")},
+		&readConcernModel{Level: types.StringValue("majority")},
+		&readPreferenceModel{Mode: types.StringValue("primary"), MaxStaleness: types.StringNull()},
+	)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if concerns.writeConcern.W != "majority" || !*concerns.writeConcern.Journal || concerns.writeConcern.WTimeout != 30*time.Second {
+		t.Fatalf("Unexpected write concern %+v", concerns.writeConcern)
+	}
+	if concerns.readConcern.Level != "majority" {
+		t.Fatalf("Unexpected read concern %+v", concerns.readConcern)
+	}
+	if concerns.readPreference.Mode() != readpref.PrimaryMode {
+		t.Fatalf("Unexpected read preference %v", concerns.readPreference)
+	}
+}
+
+func TestNewOperationConcernsNumericW(t *testing.T) {
+	concerns, diags := newOperationConcerns(
+		path.Empty(),
+		&writeConcernModel{W: types.StringValue("2"), J: types.BoolNull(), WTimeout: types.StringNull()},
+		nil,
+		nil,
+	)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if concerns.writeConcern.W != 2 || concerns.writeConcern.Journal != nil {
+		t.Fatalf("Unexpected write concern %+v", concerns.writeConcern)
+	}
+	if concerns.readConcern != nil || concerns.readPreference != nil {
+		t.Fatalf("Expected no read concern nor read preference")
+	}
+}
+
+func TestNewOperationConcernsMaxStalenessWithPrimary(t *testing.T) {
+	_, diags := newOperationConcerns(
+		path.Empty(),
+		nil,
+		nil,
+		&readPreferenceModel{Mode: types.StringValue("primary"), MaxStaleness: types.StringValue("90s")},
+	)
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestOperationConcernsOverride(t *testing.T) {
+	provider, _ := newOperationConcerns(
+		path.Empty(),
+		&writeConcernModel{W: types.StringValue("majority"), J: types.BoolNull(), WTimeout: types.StringNull()},
+		&readConcernModel{Level: types.StringValue("local")},
+		nil,
+	)
+	resource, _ := newOperationConcerns(
+		path.Empty(),
+		nil,
+		&readConcernModel{Level: types.StringValue("majority")},
+		nil,
+	)
+
+	concerns := provider.override(resource)
+	if concerns.writeConcern.W != "majority" || concerns.readConcern.Level != "majority" {
+		t.Fatalf("Unexpected concerns %+v", concerns)
+	}
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Deadline of the credential command.
+const credentialCommandTimeout = time.Minute
+
+var errMissingCommandUsername = errors.New("the credential command did not return a username")
+
+// commandCredentials is the JSON document printed by the credential command.
+type commandCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// runCredentialCommand runs the command, given as the program followed by its arguments,
+// and returns the credentials it prints on its standard output.
+func runCredentialCommand(ctx context.Context, command []string) (*commandCredentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, credentialCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, err
+	}
+
+	var credentials commandCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		return nil, fmt.Errorf("invalid output of the credential command: %w", err)
+	}
+	if credentials.Username == "" {
+		return nil, errMissingCommandUsername
+	}
+	return &credentials, nil
+}
+
+// applyCredentialCommand replaces the credentials of the configuration with the ones
+// returned by the credential command, when it is set.
+func (c *clientConfig) applyCredentialCommand(ctx context.Context) error {
+	if len(c.credentialCommand) == 0 {
+		return nil
+	}
+
+	credentials, err := runCredentialCommand(ctx, c.credentialCommand)
+	if err != nil {
+		return err
+	}
+	c.username = credentials.Username
+	c.password = credentials.Password
+	return nil
+}
+
+// readUrlFile returns the connection string stored in a file.
+func readUrlFile(name string) (string, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// writeCredentialScript writes a script printing output, as a local credential command.
+func writeCredentialScript(t *testing.T, output string) string {
+	t.Helper()
+
+	script := filepath.Join(t.TempDir(), "credentials.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho '"+output+"'\n"), 0o700); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return script
+}
+
+func TestRunCredentialCommand(t *testing.T) {
+	script := writeCredentialScript(t, `{"username": "terraform", "password": "secret"}`)
+
+	credentials, err := runCredentialCommand(context.Background(), []string{"/bin/sh", script})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if credentials.Username != "terraform" || credentials.Password != "secret" {
+		t.Fatalf("Expected terraform/secret, got %v/%v", credentials.Username, credentials.Password)
+	}
+}
+
+func TestRunCredentialCommandInvalidOutput(t *testing.T) {
+	script := writeCredentialScript(t, "terraform:secret")
+
+	_, err := runCredentialCommand(context.Background(), []string{"/bin/sh", script})
+	if err == nil {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestRunCredentialCommandMissingUsername(t *testing.T) {
+	script := writeCredentialScript(t, `{"password": "secret"}`)
+
+	_, err := runCredentialCommand(context.Background(), []string{"/bin/sh", script})
+	if err != errMissingCommandUsername {
+		t.Fatalf("Expected %v, got %v", errMissingCommandUsername, err)
+	}
+}
+
+func TestApplyCredentialCommand(t *testing.T) {
+	script := writeCredentialScript(t, `{"username": "terraform", "password": "secret"}`)
+	config := clientConfig{
+		url:               "mongodb://localhost",
+		credentialCommand: []string{"/bin/sh", script},
+	}
+
+	if err := config.applyCredentialCommand(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	opts, diags := config.clientOptions()
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if opts.Auth.Username != "terraform" || opts.Auth.Password != "secret" {
+		t.Fatalf("Expected terraform/secret, got %v/%v", opts.Auth.Username, opts.Auth.Password)
+	}
+}
+
+func TestReadUrlFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "url")
+	if err := os.WriteFile(file, []byte("mongodb://localhost:27017\n"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	url, err := readUrlFile(file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if url != "mongodb://localhost:27017" {
+		t.Fatalf("Expected mongodb://localhost:27017, got %v", url)
+	}
+}
+
+func TestClientConfigUrlFromFile(t *testing.T) {
+	t.Setenv("MONGODB_URL", "")
+	t.Setenv("MONGODB_URL_FILE", "")
+	file := filepath.Join(t.TempDir(), "url")
+	if err := os.WriteFile(file, []byte("mongodb://localhost:27017\n"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	model := newTestProviderModel()
+	model.UrlFile = types.StringValue(file)
+	config, diags := model.clientConfig(context.Background())
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if !config.urlFromFile || !config.deploymentPath().Equal(path.Root("url_file")) {
+		t.Fatalf("Expected the url to come from url_file, got %v", config.deploymentPath())
+	}
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"crypto/x509"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/auth"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// Codes of the errors returned by the server.
+const (
+	authenticationFailedCode = 18
+	namespaceNotFoundCode    = 26
+	indexNotFoundCode        = 27
+	apiVersionErrorCode      = 322
+	apiStrictErrorCode       = 323
+	apiDeprecationErrorCode  = 324
+	unknownFieldCode         = 40415
+)
+
+// describeError returns the message of an error returned by the MongoDB driver,
+// followed by hints when it is caused by a known configuration problem. The hints
+// about authentication depend on the configured authentication mechanism.
+func describeError(err error, authMechanism string) string {
+	message := err.Error()
+
+	if isAuthenticationError(err) && strings.EqualFold(authMechanism, authMechanismX509) {
+		message += "\n\nThe server rejected the client certificate as credentials. " +
+			"With the MONGODB-X509 authentication mechanism, a user named after the subject of the client certificate " +
+			"must exist in the $external database."
+	} else if isAuthenticationError(err) {
+		message += "\n\nThe server rejected the credentials. " +
+			"Check the username, password, auth_source and auth_mechanism settings."
+	}
+	if isClientCertificateRejected(err) {
+		message += "\n\nThe server rejected the client certificate during the TLS handshake. " +
+			"Check that it is signed by a certificate authority trusted by the server and that it has not expired."
+	}
+	if isServerCertificateError(err) {
+		message += "\n\nThe server certificate could not be verified. " +
+			"Check the ca_certificate_pem, ca_certificate_file and server_name attributes of the tls configuration."
+	}
+
+	if isUnsupportedServerApiError(err) {
+		message += "\n\nThe server does not support the requested Stable API version. " +
+			"Servers older than MongoDB 5.0 do not support the Stable API: set enabled = false in the server_api " +
+			"configuration of the provider, or change its version."
+	}
+	if hasErrorCode(err, apiStrictErrorCode) {
+		message += "\n\nThe command is not part of the Stable API. " +
+			"Set strict = false in the server_api configuration of the provider."
+	}
+	if hasErrorCode(err, apiDeprecationErrorCode) {
+		message += "\n\nThe command is deprecated in the Stable API. " +
+			"Set deprecation_errors = false in the server_api configuration of the provider."
+	}
+
+	return message
+}
+
+// errorCauses returns the error along with the last errors reported by each server
+// when the error comes from the server selection.
+func errorCauses(err error) []error {
+	causes := []error{err}
+
+	var selectionErr topology.ServerSelectionError
+	if errors.As(err, &selectionErr) {
+		for _, server := range selectionErr.Desc.Servers {
+			if server.LastError != nil {
+				causes = append(causes, server.LastError)
+			}
+		}
+	}
+
+	return causes
+}
+
+func isAuthenticationError(err error) bool {
+	for _, cause := range errorCauses(err) {
+		var authErr *auth.Error
+		if errors.As(cause, &authErr) {
+			return true
+		}
+	}
+	return hasErrorCode(err, authenticationFailedCode)
+}
+
+// hasErrorCode returns whether the server answered with the given error code.
+func hasErrorCode(err error, code int32) bool {
+	for _, cause := range errorCauses(err) {
+		var serverErr mongo.ServerError
+		if errors.As(cause, &serverErr) && serverErr.HasErrorCode(int(code)) {
+			return true
+		}
+		var driverErr driver.Error
+		if errors.As(cause, &driverErr) && driverErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnsupportedServerApiError returns whether the server rejected the Stable API version,
+// or does not know about the Stable API at all.
+func isUnsupportedServerApiError(err error) bool {
+	if hasErrorCode(err, apiVersionErrorCode) {
+		return true
+	}
+	return hasErrorCode(err, unknownFieldCode) && strings.Contains(err.Error(), "apiVersion")
+}
+
+func isClientCertificateRejected(err error) bool {
+	for _, cause := range errorCauses(err) {
+		message := cause.Error()
+		if strings.Contains(message, "remote error: tls: bad certificate") ||
+			strings.Contains(message, "remote error: tls: unknown certificate authority") ||
+			strings.Contains(message, "remote error: tls: certificate required") ||
+			strings.Contains(message, "remote error: tls: expired certificate") {
+			return true
+		}
+	}
+	return false
+}
+
+func isServerCertificateError(err error) bool {
+	for _, cause := range errorCauses(err) {
+		var unknownAuthorityErr x509.UnknownAuthorityError
+		var hostnameErr x509.HostnameError
+		var invalidErr x509.CertificateInvalidError
+		if errors.As(cause, &unknownAuthorityErr) || errors.As(cause, &hostnameErr) || errors.As(cause, &invalidErr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+)
+
+func TestDescribeErrorAuthenticationFailed(t *testing.T) {
+	err := mongo.CommandError{Code: 18, Message: "Authentication failed."}
+
+	message := describeError(err, "SCRAM-SHA-256")
+	if !strings.HasPrefix(message, "Authentication failed.") || !strings.Contains(message, "auth_mechanism") {
+		t.Fatalf("Expected an authentication hint, got %v", message)
+	}
+	if strings.Contains(message, "$external") {
+		t.Fatalf("Expected no X.509 hint, got %v", message)
+	}
+}
+
+func TestDescribeErrorX509AuthenticationFailed(t *testing.T) {
+	err := mongo.CommandError{Code: 18, Message: "Authentication failed."}
+
+	message := describeError(err, "MONGODB-X509")
+	if !strings.Contains(message, "$external") {
+		t.Fatalf("Expected an X.509 hint, got %v", message)
+	}
+}
+
+func TestDescribeErrorRejectedClientCertificate(t *testing.T) {
+	err := errors.New("connection() error occurred during connection handshake: remote error: tls: bad certificate")
+
+	message := describeError(err, "")
+	if !strings.Contains(message, "rejected the client certificate") {
+		t.Fatalf("Expected a client certificate hint, got %v", message)
+	}
+}
+
+func TestDescribeErrorOther(t *testing.T) {
+	err := errors.New("index not found")
+
+	message := describeError(err, "")
+	if message != "index not found" {
+		t.Fatalf("Expected the error message only, got %v", message)
+	}
+}
+
+func TestDescribeErrorUnsupportedServerApi(t *testing.T) {
+	err := driver.Error{Code: 40415, Message: "BSON field 'hello.apiVersion' is an unknown field."}
+
+	message := describeError(err, "")
+	if !strings.Contains(message, "enabled = false") {
+		t.Fatalf("Expected a Stable API hint, got %v", message)
+	}
+}
+
+func TestDescribeErrorServerApiStrict(t *testing.T) {
+	err := mongo.CommandError{Code: 323, Message: "Provided apiStrict:true, but the command validate is not in API Version 1"}
+
+	message := describeError(err, "")
+	if !strings.Contains(message, "strict = false") {
+		t.Fatalf("Expected a strict hint, got %v", message)
+	}
+}
@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// indexModification is a change of an index option that the server applies in place,
+// with a collMod command.
+type indexModification struct {
+	description string
+	option      bson.E
+	// undo reverts the previous modifications when this one fails, when set.
+	undo *indexModification
+}
+
+// indexModifications returns the changes turning the index of the state into the one of
+// the plan, in the order they must be applied. The other options require replacing the
+// index, their plan modifiers make sure they did not change.
+func indexModifications(state *indexResourceModel, plan *indexResourceModel) []indexModification {
+	var modifications []indexModification
+
+	if plan.ExpireAfterSeconds != nil && state.ExpireAfterSeconds != nil && *plan.ExpireAfterSeconds != *state.ExpireAfterSeconds {
+		modifications = append(modifications, indexModification{
+			description: fmt.Sprintf("Set expire_after_seconds of index %s.%s.%s to %d", plan.Database, plan.Collection, plan.Name, *plan.ExpireAfterSeconds),
+			option:      bson.E{Key: "expireAfterSeconds", Value: *plan.ExpireAfterSeconds},
+		})
+	}
+
+	if boolValue(plan.Unique) && !boolValue(state.Unique) {
+		// The server refuses new duplicates once the index is prepared, then checks there
+		// are none left before making it unique. The preparation is undone when there are,
+		// as the state does not record it.
+		modifications = append(modifications,
+			indexModification{
+				description: fmt.Sprintf("Prepare index %s.%s.%s to become unique", plan.Database, plan.Collection, plan.Name),
+				option:      bson.E{Key: "prepareUnique", Value: true},
+			},
+			indexModification{
+				description: fmt.Sprintf("Make index %s.%s.%s unique", plan.Database, plan.Collection, plan.Name),
+				option:      bson.E{Key: "unique", Value: true},
+				undo: &indexModification{
+					description: fmt.Sprintf("Stop preparing index %s.%s.%s to become unique", plan.Database, plan.Collection, plan.Name),
+					option:      bson.E{Key: "prepareUnique", Value: false},
+				},
+			},
+		)
+	}
+
+	if boolValue(plan.Hidden) != boolValue(state.Hidden) {
+		action := "Unhide"
+		if boolValue(plan.Hidden) {
+			action = "Hide"
+		}
+		modifications = append(modifications, indexModification{
+			description: fmt.Sprintf("%s index %s.%s.%s", action, plan.Database, plan.Collection, plan.Name),
+			option:      bson.E{Key: "hidden", Value: boolValue(plan.Hidden)},
+		})
+	}
+
+	return modifications
+}
+
+// expireAfterSecondsRequiresReplace replaces the index when it becomes a TTL index or
+// stops being one. collMod only changes the expiration of a TTL index.
+func expireAfterSecondsRequiresReplace() planmodifier.Int64 {
+	return int64planmodifier.RequiresReplaceIf(
+		func(_ context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = req.StateValue.IsNull() != req.PlanValue.IsNull()
+		},
+		"Replaces the index when expire_after_seconds is added or removed, changing it updates the index in place.",
+		"Replaces the index when `expire_after_seconds` is added or removed, changing it updates the index in place.",
+	)
+}
+
+// uniqueRequiresReplace replaces the index when it stops being unique. collMod only
+// makes an index unique.
+func uniqueRequiresReplace() planmodifier.Bool {
+	return boolplanmodifier.RequiresReplaceIf(
+		func(_ context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = req.StateValue.ValueBool() && (req.PlanValue.IsUnknown() || !req.PlanValue.ValueBool())
+		},
+		"Replaces the index when it stops being unique, making it unique updates the index in place.",
+		"Replaces the index when it stops being unique, making it unique updates the index in place.",
+	)
+}
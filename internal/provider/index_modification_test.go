@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIndexModificationsExpireAfterSeconds(t *testing.T) {
+	previous, next := int32(3600), int32(7200)
+	state := indexResourceModel{Database: "db", Collection: "events", Name: "ttl", ExpireAfterSeconds: &previous}
+	plan := state
+	plan.ExpireAfterSeconds = &next
+
+	modifications := indexModifications(&state, &plan)
+	if len(modifications) != 1 {
+		t.Fatalf("Expected 1 modification, got %v", modifications)
+	}
+	want := bson.E{Key: "expireAfterSeconds", Value: int32(7200)}
+	if modifications[0].option != want {
+		t.Fatalf("Expected %v, got %v", want, modifications[0].option)
+	}
+}
+
+func TestIndexModificationsUnique(t *testing.T) {
+	unique := true
+	state := indexResourceModel{Database: "db", Collection: "users", Name: "email"}
+	plan := state
+	plan.Unique = &unique
+
+	modifications := indexModifications(&state, &plan)
+	if len(modifications) != 2 {
+		t.Fatalf("Expected 2 modifications, got %v", modifications)
+	}
+	if modifications[0].option.Key != "prepareUnique" || modifications[1].option.Key != "unique" {
+		t.Fatalf("Expected prepareUnique then unique, got %v", modifications)
+	}
+	want := bson.E{Key: "prepareUnique", Value: false}
+	if modifications[1].undo == nil || modifications[1].undo.option != want {
+		t.Fatalf("Expected the unique step to undo prepareUnique, got %v", modifications[1].undo)
+	}
+
+	if modifications := indexModifications(&plan, &plan); len(modifications) != 0 {
+		t.Fatalf("Expected no modification, got %v", modifications)
+	}
+}
+
+func TestIndexModificationsHidden(t *testing.T) {
+	hidden := true
+	state := indexResourceModel{Database: "db", Collection: "users", Name: "email"}
+	plan := state
+	plan.Hidden = &hidden
+
+	modifications := indexModifications(&state, &plan)
+	want := bson.E{Key: "hidden", Value: true}
+	if len(modifications) != 1 || modifications[0].option != want {
+		t.Fatalf("Expected %v, got %v", want, modifications)
+	}
+
+	modifications = indexModifications(&plan, &state)
+	want = bson.E{Key: "hidden", Value: false}
+	if len(modifications) != 1 || modifications[0].option != want {
+		t.Fatalf("Expected %v, got %v", want, modifications)
+	}
+}
+
+func TestExpireAfterSecondsRequiresReplace(t *testing.T) {
+	tests := []struct {
+		state   types.Int64
+		plan    types.Int64
+		replace bool
+	}{
+		{types.Int64Value(3600), types.Int64Value(7200), false},
+		{types.Int64Null(), types.Int64Value(7200), true},
+		{types.Int64Value(3600), types.Int64Null(), true},
+	}
+
+	for _, test := range tests {
+		req := planmodifier.Int64Request{
+			State:      nonNullState(),
+			Plan:       nonNullPlan(),
+			StateValue: test.state,
+			PlanValue:  test.plan,
+		}
+		resp := &planmodifier.Int64Response{PlanValue: test.plan}
+		expireAfterSecondsRequiresReplace().PlanModifyInt64(context.Background(), req, resp)
+		if resp.RequiresReplace != test.replace {
+			t.Fatalf("Expected %v from %v to %v, got %v", test.replace, test.state, test.plan, resp.RequiresReplace)
+		}
+	}
+}
+
+func TestUniqueRequiresReplace(t *testing.T) {
+	tests := []struct {
+		state   types.Bool
+		plan    types.Bool
+		replace bool
+	}{
+		{types.BoolNull(), types.BoolValue(true), false},
+		{types.BoolValue(false), types.BoolValue(true), false},
+		{types.BoolValue(true), types.BoolNull(), true},
+		{types.BoolValue(true), types.BoolValue(false), true},
+	}
+
+	for _, test := range tests {
+		req := planmodifier.BoolRequest{
+			State:      nonNullState(),
+			Plan:       nonNullPlan(),
+			StateValue: test.state,
+			PlanValue:  test.plan,
+		}
+		resp := &planmodifier.BoolResponse{PlanValue: test.plan}
+		uniqueRequiresReplace().PlanModifyBool(context.Background(), req, resp)
+		if resp.RequiresReplace != test.replace {
+			t.Fatalf("Expected %v from %v to %v, got %v", test.replace, test.state, test.plan, resp.RequiresReplace)
+		}
+	}
+}
+
+// nonNullState returns a state of an existing resource, as the plan modifiers requiring
+// replacement ignore the resources being created.
+func nonNullState() tfsdk.State {
+	return tfsdk.State{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})}
+}
+
+// nonNullPlan returns a plan of a resource that is not destroyed.
+func nonNullPlan() tfsdk.Plan {
+	return tfsdk.Plan{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})}
+}
+
+func TestModifyPlanHiddenServerVersion(t *testing.T) {
+	tests := []struct {
+		state   interface{}
+		plan    interface{}
+		failure bool
+	}{
+		// Explicitly visible indexes are the default, older servers accept them.
+		{nil, false, false},
+		{false, false, false},
+		{true, true, false},
+		{nil, true, true},
+		{false, true, true},
+	}
+
+	r := &indexResource{clusters: &mongodbProviderClusters{
+		defaultCluster: &mongodbProviderData{version: []int32{4, 2, 24, 0}},
+	}}
+	for _, test := range tests {
+		req := resource.ModifyPlanRequest{
+			State: tfsdk.State(newTestIndexValue(t, map[string]tftypes.Value{"hidden": tftypes.NewValue(tftypes.Bool, test.state)})),
+			Plan:  tfsdk.Plan(newTestIndexValue(t, map[string]tftypes.Value{"hidden": tftypes.NewValue(tftypes.Bool, test.plan)})),
+		}
+		resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+		r.ModifyPlan(context.Background(), req, resp)
+		if resp.Diagnostics.HasError() != test.failure {
+			t.Fatalf("Expected failure %v from %v to %v, got %v", test.failure, test.state, test.plan, resp.Diagnostics)
+		}
+	}
+}
+
+// newTestIndexValue returns a state of the index resource where every attribute is null,
+// except the given ones.
+func newTestIndexValue(t *testing.T, values map[string]tftypes.Value) tfsdk.State {
+	t.Helper()
+
+	schemaResp := &resource.SchemaResponse{}
+	NewIndexResource().Schema(context.Background(), resource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", schemaResp.Diagnostics)
+	}
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("Expected an object type, got %T", schemaResp.Schema.Type().TerraformType(context.Background()))
+	}
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+
+	return tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(objectType, attributes),
+	}
+}
+
+func TestModifyPlanUniqueServerVersion(t *testing.T) {
+	r := &indexResource{clusters: &mongodbProviderClusters{
+		defaultCluster: &mongodbProviderData{version: []int32{5, 0, 0, 0}},
+	}}
+	unique := func(value interface{}) map[string]tftypes.Value {
+		return map[string]tftypes.Value{"unique": tftypes.NewValue(tftypes.Bool, value)}
+	}
+
+	// Unique indexes are created by older servers.
+	req := resource.ModifyPlanRequest{
+		State: tfsdk.State{Raw: tftypes.NewValue(newTestIndexValue(t, nil).Raw.Type(), nil)},
+		Plan:  tfsdk.Plan(newTestIndexValue(t, unique(true))),
+	}
+	resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(context.Background(), req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+
+	// Making an existing index unique requires MongoDB 6.0.
+	req.State = newTestIndexValue(t, unique(false))
+	resp = &resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(context.Background(), req, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
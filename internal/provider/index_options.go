@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// indexOptionsDocument holds the options of an index that the driver does not parse into
+// mongo.IndexSpecification, as listed by the server.
+type indexOptionsDocument struct {
+	Collation          *collationDocument `bson:"collation"`
+	WildcardProjection bson.D             `bson:"wildcardProjection"`
+	Background         *bool              `bson:"background"`
+}
+
+// collationDocument is a collation as listed by the server, which fills every option left
+// unset with the default of the locale.
+type collationDocument struct {
+	Locale          string  `bson:"locale"`
+	CaseLevel       *bool   `bson:"caseLevel"`
+	CaseFirst       *string `bson:"caseFirst"`
+	Strength        *int32  `bson:"strength"`
+	NumericOrdering *bool   `bson:"numericOrdering"`
+	Alternate       *string `bson:"alternate"`
+	MaxVariable     *string `bson:"maxVariable"`
+	Normalization   *bool   `bson:"normalization"`
+	Backwards       *bool   `bson:"backwards"`
+}
+
+// Defaults of the collation options for most locales.
+var defaultCollation = collation{
+	CaseLevel:       boolPointer(false),
+	CaseFirst:       stringPointer("off"),
+	Strength:        intPointer(3),
+	NumericOrdering: boolPointer(false),
+	Alternate:       stringPointer("non-ignorable"),
+	MaxVariable:     stringPointer("punct"),
+	Normalization:   boolPointer(false),
+	Backwards:       boolPointer(false),
+}
+
+// Defaults of the collation options of the locales differing from most locales.
+var localeCollationDefaults = map[string]collation{
+	"da":    {CaseFirst: stringPointer("upper")},
+	"mt":    {CaseFirst: stringPointer("upper")},
+	"fr_CA": {Backwards: boolPointer(true)},
+	"th":    {Alternate: stringPointer("shifted")},
+}
+
+// simpleCollationLocale compares strings by their binary value. The server lists no
+// collation for the indexes using it.
+const simpleCollationLocale = "simple"
+
+// collationDefaults returns the defaults of the collation options for the locale, the
+// variants of a locale, such as de@collation=phonebook, sharing its defaults.
+func collationDefaults(locale string) collation {
+	locale, _, _ = strings.Cut(locale, "@")
+	defaults := defaultCollation
+	localeDefaults, ok := localeCollationDefaults[locale]
+	if !ok {
+		return defaults
+	}
+	if localeDefaults.CaseFirst != nil {
+		defaults.CaseFirst = localeDefaults.CaseFirst
+	}
+	if localeDefaults.Alternate != nil {
+		defaults.Alternate = localeDefaults.Alternate
+	}
+	if localeDefaults.Backwards != nil {
+		defaults.Backwards = localeDefaults.Backwards
+	}
+	return defaults
+}
+
+// readIndexOptions sets the collation, wildcard projection and background of the state
+// from an index listed by the server.
+func readIndexOptions(rawIndex bson.Raw, declared *indexResourceModel, state *indexResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var options indexOptionsDocument
+	err := bson.Unmarshal(rawIndex, &options)
+	if err == nil {
+		state.WildcardProjection, err = readWildcardProjection(options.WildcardProjection)
+	}
+	if err != nil {
+		diags.AddError(
+			"Unable to parse index options",
+			"An unexpected error occurred when parsing the collation, wildcard projection and background of the index. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	state.Collation = readCollation(options.Collation, declared.Collation)
+	background := options.Background != nil && *options.Background
+	state.Background = readIndexOption(&background, declared.Background, false)
+	return diags
+}
+
+// readCollation returns the collation of the resource from the one listed by the server.
+// The options of a declared collation are only kept when they are declared, as the
+// server fills the other ones. Otherwise, such as on import, the options differing from
+// the defaults of the locale are kept.
+func readCollation(document *collationDocument, declared *collation) *collation {
+	simple := declared != nil && declared.Locale == simpleCollationLocale
+	if document == nil && !simple {
+		return nil
+	}
+	if document == nil || document.Locale == simpleCollationLocale {
+		return &collation{Locale: simpleCollationLocale}
+	}
+
+	var strength *int
+	if document.Strength != nil {
+		strength = intPointer(int(*document.Strength))
+	}
+
+	// Compared to no defaults, a declared collation only keeps its declared options.
+	defaults, declaredOptions := collationDefaults(document.Locale), collation{}
+	if declared != nil {
+		defaults, declaredOptions = collation{}, *declared
+	}
+
+	return &collation{
+		Locale:          document.Locale,
+		CaseLevel:       readCollationOption(document.CaseLevel, declaredOptions.CaseLevel, defaults.CaseLevel),
+		CaseFirst:       readCollationOption(document.CaseFirst, declaredOptions.CaseFirst, defaults.CaseFirst),
+		Strength:        readCollationOption(strength, declaredOptions.Strength, defaults.Strength),
+		NumericOrdering: readCollationOption(document.NumericOrdering, declaredOptions.NumericOrdering, defaults.NumericOrdering),
+		Alternate:       readCollationOption(document.Alternate, declaredOptions.Alternate, defaults.Alternate),
+		MaxVariable:     readCollationOption(document.MaxVariable, declaredOptions.MaxVariable, defaults.MaxVariable),
+		Normalization:   readCollationOption(document.Normalization, declaredOptions.Normalization, defaults.Normalization),
+		Backwards:       readCollationOption(document.Backwards, declaredOptions.Backwards, defaults.Backwards),
+	}
+}
+
+// readCollationOption returns the value of a collation option listed by the server when it
+// is declared, or when it differs from the default. Without default, only the declared
+// options are kept.
+func readCollationOption[T comparable](value *T, declared *T, defaultValue *T) *T {
+	if declared != nil || (defaultValue != nil && value != nil && *value != *defaultValue) {
+		return value
+	}
+	return nil
+}
+
+// readWildcardProjection converts the wildcard projection listed by the server, whose
+// values may be numbers or booleans.
+func readWildcardProjection(document bson.D) (*map[string]int32, error) {
+	if document == nil {
+		return nil, nil
+	}
+
+	projection := map[string]int32{}
+	for _, field := range document {
+		switch value := field.Value.(type) {
+		case bool:
+			projection[field.Key] = 0
+			if value {
+				projection[field.Key] = 1
+			}
+		default:
+			number, ok := int32Value(value)
+			if !ok {
+				return nil, fmt.Errorf("unexpected projection %v of the field %s", field.Value, field.Key)
+			}
+			projection[field.Key] = number
+		}
+	}
+	return &projection, nil
+}
+
+func boolPointer(value bool) *bool {
+	return &value
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func intPointer(value int) *int {
+	return &value
+}
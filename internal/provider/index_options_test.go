@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Collation listed by the server for an index created with the fr locale and a strength of 2.
+var frenchCollationDocument = collationDocument{
+	Locale:          "fr",
+	CaseLevel:       boolPointer(false),
+	CaseFirst:       stringPointer("off"),
+	Strength:        func() *int32 { strength := int32(2); return &strength }(),
+	NumericOrdering: boolPointer(false),
+	Alternate:       stringPointer("non-ignorable"),
+	MaxVariable:     stringPointer("punct"),
+	Normalization:   boolPointer(false),
+	Backwards:       boolPointer(false),
+}
+
+func TestReadCollationKeepsDeclaredOptions(t *testing.T) {
+	declared := &collation{Locale: "fr", Strength: intPointer(2), Backwards: boolPointer(false)}
+
+	read := readCollation(&frenchCollationDocument, declared)
+	if !reflect.DeepEqual(read, declared) {
+		t.Fatalf("Expected %+v, got %+v", declared, read)
+	}
+}
+
+func TestReadCollationOnImport(t *testing.T) {
+	read := readCollation(&frenchCollationDocument, nil)
+
+	want := &collation{Locale: "fr", Strength: intPointer(2)}
+	if !reflect.DeepEqual(read, want) {
+		t.Fatalf("Expected %+v, got %+v", want, read)
+	}
+}
+
+func TestReadCollationOnImportWithLocaleDefaults(t *testing.T) {
+	tests := []struct {
+		document collationDocument
+		want     collation
+	}{
+		// Danish sorts the uppercase letters first by default.
+		{
+			document: collationDocument{Locale: "da", CaseFirst: stringPointer("upper")},
+			want:     collation{Locale: "da"},
+		},
+		// Thai ignores the spaces and punctuation by default.
+		{
+			document: collationDocument{Locale: "th", Alternate: stringPointer("shifted")},
+			want:     collation{Locale: "th"},
+		},
+		{
+			document: collationDocument{Locale: "da", CaseFirst: stringPointer("off")},
+			want:     collation{Locale: "da", CaseFirst: stringPointer("off")},
+		},
+	}
+
+	for _, test := range tests {
+		if read := readCollation(&test.document, nil); !reflect.DeepEqual(*read, test.want) {
+			t.Fatalf("Expected %+v, got %+v", test.want, *read)
+		}
+	}
+}
+
+func TestReadSimpleCollation(t *testing.T) {
+	declared := &collation{Locale: "simple"}
+	want := &collation{Locale: "simple"}
+
+	if read := readCollation(nil, declared); !reflect.DeepEqual(read, want) {
+		t.Fatalf("Expected %+v, got %+v", want, read)
+	}
+	if read := readCollation(&collationDocument{Locale: "simple"}, nil); !reflect.DeepEqual(read, want) {
+		t.Fatalf("Expected %+v, got %+v", want, read)
+	}
+}
+
+func TestReadCollationDrift(t *testing.T) {
+	declared := &collation{Locale: "de", Strength: intPointer(3)}
+
+	read := readCollation(&frenchCollationDocument, declared)
+	if read.Locale != "fr" || *read.Strength != 2 {
+		t.Fatalf("Expected the collation of the server, got %+v", read)
+	}
+	if readCollation(nil, declared) != nil {
+		t.Fatalf("Expected no collation")
+	}
+}
+
+func TestReadWildcardProjection(t *testing.T) {
+	projection, err := readWildcardProjection(bson.D{
+		{Key: "a", Value: int32(1)},
+		{Key: "b", Value: false},
+		{Key: "c", Value: float64(1)},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]int32{"a": 1, "b": 0, "c": 1}
+	if !reflect.DeepEqual(*projection, want) {
+		t.Fatalf("Expected %v, got %v", want, *projection)
+	}
+
+	if _, err := readWildcardProjection(bson.D{{Key: "a", Value: "yes"}}); err == nil {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestReadIndexOptions(t *testing.T) {
+	rawIndex, err := bson.Marshal(bson.D{
+		{Key: "v", Value: int32(2)},
+		{Key: "key", Value: bson.D{{Key: "$**", Value: int32(1)}}},
+		{Key: "name", Value: "wildcard"},
+		{Key: "background", Value: true},
+		{Key: "wildcardProjection", Value: bson.D{{Key: "a", Value: int32(1)}}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var state indexResourceModel
+	if diags := readIndexOptions(rawIndex, &indexResourceModel{}, &state); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if state.Background == nil || !*state.Background {
+		t.Fatalf("Expected a background index, got %v", state.Background)
+	}
+	if state.WildcardProjection == nil || (*state.WildcardProjection)["a"] != 1 {
+		t.Fatalf("Expected the wildcard projection, got %v", state.WildcardProjection)
+	}
+	if state.Collation != nil {
+		t.Fatalf("Expected no collation, got %+v", state.Collation)
+	}
+}
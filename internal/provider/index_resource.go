@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
-	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -24,22 +26,26 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &indexResource{}
-	_ resource.ResourceWithConfigure   = &indexResource{}
-	_ resource.ResourceWithImportState = &indexResource{}
+	_ resource.Resource                   = &indexResource{}
+	_ resource.ResourceWithConfigure      = &indexResource{}
+	_ resource.ResourceWithImportState    = &indexResource{}
+	_ resource.ResourceWithModifyPlan     = &indexResource{}
+	_ resource.ResourceWithValidateConfig = &indexResource{}
 )
 
 // indexResource is the resource implementation.
 type indexResource struct {
-	client *mongo.Client
+	clusters *mongodbProviderClusters
 }
 
 // indexResourceModel maps the resource schema data.
 type indexResourceModel struct {
+	Cluster                 *string           `tfsdk:"cluster"`
 	Database                string            `tfsdk:"database"`
 	Collection              string            `tfsdk:"collection"`
 	Name                    string            `tfsdk:"name"`
@@ -51,6 +57,16 @@ type indexResourceModel struct {
 	PartialFilterExpression *string           `tfsdk:"partial_filter_expression"`
 	Collation               *collation        `tfsdk:"collation"`
 	Background              *bool             `tfsdk:"background"`
+	Hidden                  *bool             `tfsdk:"hidden"`
+	FailOnMissing           *bool             `tfsdk:"fail_on_missing"`
+	Weights                 *map[string]int32 `tfsdk:"weights"`
+	DefaultLanguage         *string           `tfsdk:"default_language"`
+	LanguageOverride        *string           `tfsdk:"language_override"`
+	TextIndexVersion        *int32            `tfsdk:"text_index_version"`
+
+	WriteConcern   *writeConcernModel   `tfsdk:"write_concern"`
+	ReadConcern    *readConcernModel    `tfsdk:"read_concern"`
+	ReadPreference *readPreferenceModel `tfsdk:"read_preference"`
 
 	// see https://developer.hashicorp.com/terraform/plugin/framework/acctests#implement-id-attribute
 	Id types.String `tfsdk:"id"`
@@ -85,17 +101,17 @@ func (d *indexResource) Configure(ctx context.Context, req resource.ConfigureReq
 		return
 	}
 
-	client, ok := req.ProviderData.(*mongo.Client)
+	clusters, ok := req.ProviderData.(*mongodbProviderClusters)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *mongo.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *mongodbProviderClusters, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.clusters = clusters
 	tflog.Info(ctx, "Configured MongoDB index resource")
 }
 
@@ -109,6 +125,7 @@ func (r *indexResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 	resp.Schema = schema.Schema{
 		Description: "Create indexes in MongoDB.",
 		Attributes: map[string]schema.Attribute{
+			"cluster": clusterResourceAttribute(),
 			"database": schema.StringAttribute{
 				Description: "Name of the database where to create the index.",
 				Required:    true,
@@ -143,11 +160,8 @@ func (r *indexResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 							Required:    true,
 						},
 						"type": schema.StringAttribute{
-							Description: "The type of index for this field.",
+							Description: "The type of index for this field: asc, desc, text, hashed, 2d or 2dsphere.",
 							Required:    true,
-							Validators: []validator.String{
-								stringvalidator.NoneOf("text"),
-							},
 						},
 					},
 				},
@@ -163,17 +177,18 @@ func (r *indexResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 			"expire_after_seconds": schema.Int64Attribute{
-				Description: "Documents ttl in seconds for ttl indexes.",
+				Description: "Documents ttl in seconds for ttl indexes. Changing it updates the index in place.",
 				Optional:    true,
 				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.RequiresReplace(),
+					expireAfterSecondsRequiresReplace(),
 				},
 			},
 			"unique": schema.BoolAttribute{
-				Description: "Is it a unique index.",
-				Optional:    true,
+				Description: "Is it a unique index. Making an index unique updates it in place, which requires MongoDB 6.0 or later " +
+					"and fails if the collection has duplicates.",
+				Optional: true,
 				PlanModifiers: []planmodifier.Bool{
-					boolplanmodifier.RequiresReplace(),
+					uniqueRequiresReplace(),
 				},
 			},
 			"wildcard_projection": schema.MapAttribute{
@@ -198,6 +213,51 @@ func (r *indexResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					boolplanmodifier.RequiresReplace(),
 				},
 			},
+			"weights": schema.MapAttribute{
+				Description: "Weights of the fields of a text index, from 1 to 99999. The fields default to a weight of 1.",
+				ElementType: types.Int64Type,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Map{
+					mapvalidator.ValueInt64sAre(int64validator.Between(1, 99999)),
+				},
+			},
+			"default_language": schema.StringAttribute{
+				Description: "Language of a text index, which sets the stop words and the stemming rules. Defaults to english.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"language_override": schema.StringAttribute{
+				Description: "Field of the documents overriding the default_language of a text index. Defaults to language.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"text_index_version": schema.Int64Attribute{
+				Description: "Version of a text index. Defaults to the latest version supported by the server.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int64{
+					int64validator.OneOf(1, 2, 3),
+				},
+			},
+			"hidden": schema.BoolAttribute{
+				Description: "Hide the index from the query planner, while keeping it up to date. Changing it updates the index in place. " +
+					"Requires MongoDB 4.4 or later.",
+				Optional: true,
+			},
+			"fail_on_missing": schema.BoolAttribute{
+				Description: "Fail the refresh when the index, its collection or its database was dropped outside of Terraform. " +
+					"Defaults to false: the index is removed from the state and planned to be created again.",
+				Optional: true,
+			},
 			"collation": schema.SingleNestedAttribute{
 				Description: "Index collation.",
 				Optional:    true,
@@ -270,6 +330,9 @@ func (r *indexResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					},
 				},
 			},
+			"write_concern":   writeConcernResourceAttribute(),
+			"read_concern":    readConcernResourceAttribute(),
+			"read_preference": readPreferenceResourceAttribute(),
 			// see https://developer.hashicorp.com/terraform/plugin/framework/acctests#implement-id-attribute
 			"id": schema.StringAttribute{
 				Computed:           true,
@@ -281,6 +344,14 @@ func (r *indexResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *indexResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.clusters.maskLogs(ctx)
+	defer func() { resp.Diagnostics = r.clusters.redactDiagnostics(resp.Diagnostics) }()
+
+	resp.Diagnostics.Append(r.clusters.checkWritable("create")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Retrieve values from plan
 	var plan indexResourceModel
 	diags := req.Plan.Get(ctx, &plan)
@@ -300,8 +371,13 @@ func (r *indexResource) Create(ctx context.Context, req resource.CreateRequest,
 		keys = append(keys, bson.E{Key: key.Field, Value: convertToMongoIndexType(key.Type)})
 	}
 
-	db := r.client.Database(databaseName)
-	collection := db.Collection(collectionName)
+	providerData, diags := r.clusters.resourceCluster(plan.Cluster)
+	resp.Diagnostics.Append(diags...)
+	concerns, diags := r.concerns(&plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	options := &options.IndexOptions{
 		Name:               &indexName,
@@ -310,10 +386,17 @@ func (r *indexResource) Create(ctx context.Context, req resource.CreateRequest,
 		Unique:             plan.Unique,
 		Collation:          plan.Collation.toMongoCollation(),
 		Background:         plan.Background,
+		Hidden:             plan.Hidden,
+		DefaultLanguage:    plan.DefaultLanguage,
+		LanguageOverride:   plan.LanguageOverride,
+		TextVersion:        plan.TextIndexVersion,
 	}
 	if plan.WildcardProjection != nil {
 		options.WildcardProjection = plan.WildcardProjection
 	}
+	if weights := plan.textIndexWeights(); weights != nil {
+		options.Weights = weights
+	}
 	if plan.PartialFilterExpression != nil {
 		var filterExpr bson.M
 		err := json.Unmarshal([]byte(*plan.PartialFilterExpression), &filterExpr)
@@ -329,13 +412,47 @@ func (r *indexResource) Create(ctx context.Context, req resource.CreateRequest,
 		options.PartialFilterExpression = filterExpr
 	}
 
-	name, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: options})
+	if r.clusters.preview != nil {
+		writeConcern := providerData.concerns.override(concerns).writeConcern
+		resp.Diagnostics.Append(r.clusters.preview.record(
+			stringValue(plan.Cluster),
+			databaseName,
+			fmt.Sprintf("Create index %s.%s.%s", databaseName, collectionName, indexName),
+			createIndexesCommand(collectionName, keys, options, writeConcern),
+		)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		r.clusters.preview.recordCreatedIndex(indexId{
+			cluster:    stringValue(plan.Cluster),
+			database:   databaseName,
+			collection: collectionName,
+			indexName:  indexName,
+		})
+
+		plan.Id = types.StringValue("to_be_ignored")
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	resp.Diagnostics.Append(r.clusters.maintenance.checkMaintenanceWindow("create")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var name string
+	err := providerData.runLimited(ctx, databaseName, collectionName, func() error {
+		var err error
+		collection := providerData.database(databaseName, concerns).Collection(collectionName)
+		name, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: options})
+		return err
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create index",
 			"An unexpected error occurred when creating index. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Error: "+err.Error(),
+				"Error: "+providerData.describeError(err),
 		)
 		return
 	}
@@ -355,6 +472,9 @@ func (r *indexResource) Create(ctx context.Context, req resource.CreateRequest,
 
 // Read refreshes the Terraform state with the latest data.
 func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.clusters.maskLogs(ctx)
+	defer func() { resp.Diagnostics = r.clusters.redactDiagnostics(resp.Diagnostics) }()
+
 	// Get current state
 	var state indexResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -369,15 +489,28 @@ func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 	tflog.Debug(ctx, fmt.Sprintf("Getting index %s.%s.%s", databaseName, collectionName, indexName))
 
-	db := r.client.Database(databaseName)
-	collection := db.Collection(collectionName)
-	indexes, err := collection.Indexes().ListSpecifications(ctx)
+	providerData, diags := r.clusters.resourceCluster(state.Cluster)
+	resp.Diagnostics.Append(diags...)
+	concerns, diags := r.concerns(&state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var collection *mongo.Collection
+	var indexes []*mongo.IndexSpecification
+	err := providerData.retryOnAuthenticationFailure(ctx, func() error {
+		var err error
+		collection = providerData.database(databaseName, concerns).Collection(collectionName)
+		indexes, err = collection.Indexes().ListSpecifications(ctx)
+		return err
+	})
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to list indexes",
 			"An unexpected error occurred when listing indexes. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Error: "+err.Error(),
+				"Error: "+providerData.describeError(err),
 		)
 		return
 	}
@@ -390,10 +523,27 @@ func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		}
 	}
 
+	// In preview mode, the index created by this provider only exists once the script is
+	// run. The driver lists no indexes when the collection does not exist yet either. The
+	// other missing indexes were dropped outside of Terraform.
+	id := indexId{cluster: stringValue(state.Cluster), database: databaseName, collection: collectionName, indexName: indexName}
+	if foundIndex == nil && r.clusters.preview != nil && r.clusters.preview.createdIndex(id) {
+		tflog.Debug(ctx, fmt.Sprintf("Keeping index %s.%s.%s of the preview script", databaseName, collectionName, indexName))
+		return
+	}
+
+	// The driver lists no indexes when the collection or the database has been dropped too.
+	if foundIndex == nil && !boolValue(state.FailOnMissing) {
+		tflog.Warn(ctx, fmt.Sprintf("Removing index %s.%s.%s from the state, it does not exist", databaseName, collectionName, indexName))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
 	if foundIndex == nil {
 		resp.Diagnostics.AddError(
 			"Unable to find index with name "+indexName,
-			"The requested index does not exist. ",
+			"The requested index does not exist. "+
+				"Unset fail_on_missing to remove it from the state and create it again.",
 		)
 		return
 	}
@@ -412,6 +562,9 @@ func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	// The declared keys and options, to read the options left to their defaults back into them.
+	declared := state
+
 	state.Keys = make([]indexKey, 0)
 	for _, v := range foundKeys {
 		typ, err := convertToTfIndexType(v.Value)
@@ -427,9 +580,12 @@ func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		state.Keys = append(state.Keys, indexKey{Field: v.Key, Type: typ})
 	}
 
-	state.Sparse = foundIndex.Sparse
+	// The server only lists sparse and unique when they are set.
+	sparse := foundIndex.Sparse != nil && *foundIndex.Sparse
+	state.Sparse = readIndexOption(&sparse, declared.Sparse, false)
 	state.ExpireAfterSeconds = foundIndex.ExpireAfterSeconds
-	state.Unique = foundIndex.Unique
+	unique := foundIndex.Unique != nil && *foundIndex.Unique
+	state.Unique = readIndexOption(&unique, declared.Unique, false)
 	state.Id = types.StringValue("to_be_ignored")
 
 	indexesCursor, err := collection.Indexes().List(ctx)
@@ -438,7 +594,7 @@ func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 			"Unable to list indexes",
 			"An unexpected error occurred when listing indexes. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Error: "+err.Error(),
+				"Error: "+providerData.describeError(err),
 		)
 		return
 	}
@@ -462,6 +618,25 @@ func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 					}
 				}
 			}
+
+			resp.Diagnostics.Append(readIndexOptions(rawIndex, &declared, &state)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+
+			// The server only lists hidden when the index is hidden.
+			hidden := false
+			if value, ok := rawIndex.Lookup("hidden").BooleanOK(); ok {
+				hidden = value
+			}
+			state.Hidden = readIndexOption(&hidden, declared.Hidden, false)
+
+			if rawIndex.Lookup("weights").Type != 0 {
+				resp.Diagnostics.Append(readTextIndex(rawIndex, foundKeys, &declared, &state)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+			}
 			break
 		}
 	}
@@ -471,7 +646,7 @@ func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 			"Unable to list indexes",
 			"An unexpected error occurred when listing indexes. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Error: "+err.Error(),
+				"Error: "+providerData.describeError(err),
 		)
 		return
 	}
@@ -487,15 +662,100 @@ func (r *indexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
+// The options the server can change in place are updated with collMod, the other
+// changes of the index result in resource recreation.
 func (r *indexResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError(
-		"An update has been triggered when none should have been.",
-		" Changes in index should always result in resource recreation. ",
-	)
+	ctx = r.clusters.maskLogs(ctx)
+	defer func() { resp.Diagnostics = r.clusters.redactDiagnostics(resp.Diagnostics) }()
+
+	resp.Diagnostics.Append(r.clusters.checkWritable("update")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Retrieve values from plan and state
+	var plan, state indexResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	databaseName := plan.Database
+	collectionName := plan.Collection
+	indexName := plan.Name
+
+	if modifications := indexModifications(&state, &plan); len(modifications) > 0 {
+		providerData, diags := r.clusters.resourceCluster(plan.Cluster)
+		resp.Diagnostics.Append(diags...)
+		concerns, diags := r.concerns(&plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		writeConcern := providerData.concerns.override(concerns).writeConcern
+
+		if r.clusters.preview == nil {
+			resp.Diagnostics.Append(r.clusters.maintenance.checkMaintenanceWindow("update")...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		runCommand := func(command bson.D) error {
+			return providerData.runLimited(ctx, databaseName, collectionName, func() error {
+				// Commands are sent to the primary, whatever the read preference of the resource.
+				runOptions := options.RunCmd().SetReadPreference(readpref.Primary())
+				return providerData.database(databaseName, concerns).RunCommand(ctx, command, runOptions).Err()
+			})
+		}
+
+		for _, modification := range modifications {
+			command := collModIndexCommand(collectionName, indexName, modification.option, writeConcern)
+			if r.clusters.preview != nil {
+				resp.Diagnostics.Append(r.clusters.preview.record(stringValue(plan.Cluster), databaseName, modification.description, command)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				continue
+			}
+
+			tflog.Debug(ctx, modification.description)
+			err := runCommand(command)
+			if err != nil {
+				detail := "An unexpected error occurred when updating the index in place (" + modification.description + "). " +
+					"If the error is not clear, please contact the provider developers.\n\n" +
+					"Error: " + providerData.describeError(err)
+				if modification.undo != nil {
+					tflog.Debug(ctx, modification.undo.description)
+					undoCommand := collModIndexCommand(collectionName, indexName, modification.undo.option, writeConcern)
+					if undoErr := runCommand(undoCommand); undoErr != nil {
+						detail += "\n\nThe previous changes of the index could not be undone (" + modification.undo.description + "), " +
+							"run a collMod command with " + modification.undo.option.Key + " set to " + fmt.Sprint(modification.undo.option.Value) + " to undo them.\n\n" +
+							"Error: " + providerData.describeError(undoErr)
+					}
+				}
+				resp.Diagnostics.AddError("Unable to update index", detail)
+				return
+			}
+		}
+	}
+
+	plan.Id = types.StringValue("to_be_ignored")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *indexResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.clusters.maskLogs(ctx)
+	defer func() { resp.Diagnostics = r.clusters.redactDiagnostics(resp.Diagnostics) }()
+
+	resp.Diagnostics.Append(r.clusters.checkWritable("delete")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Retrieve values from state
 	var state indexResourceModel
 	diags := req.State.Get(ctx, &state)
@@ -511,16 +771,45 @@ func (r *indexResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	tflog.Debug(ctx, fmt.Sprintf("Dropping index %s.%s.%s", databaseName, collectionName, indexName))
 
-	db := r.client.Database(databaseName)
-	collection := db.Collection(collectionName)
+	providerData, diags := r.clusters.resourceCluster(state.Cluster)
+	resp.Diagnostics.Append(diags...)
+	concerns, diags := r.concerns(&state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.clusters.preview != nil {
+		writeConcern := providerData.concerns.override(concerns).writeConcern
+		resp.Diagnostics.Append(r.clusters.preview.record(
+			stringValue(state.Cluster),
+			databaseName,
+			fmt.Sprintf("Drop index %s.%s.%s", databaseName, collectionName, indexName),
+			dropIndexesCommand(collectionName, indexName, writeConcern),
+		)...)
+		return
+	}
+
+	resp.Diagnostics.Append(r.clusters.maintenance.checkMaintenanceWindow("delete")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	_, err := collection.Indexes().DropOne(ctx, indexName)
+	err := providerData.runLimited(ctx, databaseName, collectionName, func() error {
+		collection := providerData.database(databaseName, concerns).Collection(collectionName)
+		_, err := collection.Indexes().DropOne(ctx, indexName)
+		return err
+	})
+	if hasErrorCode(err, indexNotFoundCode) || hasErrorCode(err, namespaceNotFoundCode) {
+		tflog.Warn(ctx, fmt.Sprintf("Index %s.%s.%s was already dropped", databaseName, collectionName, indexName))
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to update (drop) index",
 			"An unexpected error occurred when creating index. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
-				"Error: "+err.Error(),
+				"Error: "+providerData.describeError(err),
 		)
 		return
 	}
@@ -528,12 +817,79 @@ func (r *indexResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	tflog.Debug(ctx, fmt.Sprintf("Dropped index %s.%s.%s", databaseName, collectionName, indexName))
 }
 
+// ValidateConfig checks the text options of the index, so that errors are reported at plan time.
+func (r *indexResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config textIndexConfig
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("keys"), &config.Keys)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("weights"), &config.Weights)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("default_language"), &config.DefaultLanguage)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("language_override"), &config.LanguageOverride)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("text_index_version"), &config.TextIndexVersion)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(config.validate(ctx)...)
+}
+
+// ModifyPlan checks that the deployment supports the options of the planned index.
+func (r *indexResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the resource is destroyed, or when the provider is not configured yet.
+	if req.Plan.Raw.IsNull() || r.clusters == nil {
+		return
+	}
+	ctx = r.clusters.maskLogs(ctx)
+	defer func() { resp.Diagnostics = r.clusters.redactDiagnostics(resp.Diagnostics) }()
+
+	var hidden, stateHidden, unique, stateUnique types.Bool
+	var cluster types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("hidden"), &hidden)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("unique"), &unique)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("cluster"), &cluster)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("hidden"), &stateHidden)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("unique"), &stateUnique)...)
+	}
+	if resp.Diagnostics.HasError() || cluster.IsUnknown() {
+		return
+	}
+
+	// Indexes are visible by default, the server is only asked for its version when the
+	// index is hidden or unhidden. Unique indexes are only created with createIndexes
+	// by older servers, making an existing index unique requires collMod.
+	checkHidden := !hidden.IsUnknown() && hidden.ValueBool() != stateHidden.ValueBool()
+	checkUnique := !req.State.Raw.IsNull() && unique.ValueBool() && !stateUnique.ValueBool()
+	if !checkHidden && !checkUnique {
+		return
+	}
+
+	providerData, err := r.clusters.cluster(cluster.ValueString())
+	if err != nil {
+		// Reported by the operations of the resource.
+		return
+	}
+	if checkHidden {
+		resp.Diagnostics.Append(providerData.checkServerVersion(ctx, path.Root("hidden"), "Hidden indexes", hiddenIndexMinVersion)...)
+	}
+	if checkUnique {
+		resp.Diagnostics.Append(providerData.checkServerVersion(ctx, path.Root("unique"), "Unique conversions of existing indexes", uniqueConversionMinVersion)...)
+	}
+}
+
+// concerns returns the concerns of the resource, which override the ones of the provider.
+func (r *indexResource) concerns(model *indexResourceModel) (operationConcerns, diag.Diagnostics) {
+	return newOperationConcerns(path.Empty(), model.WriteConcern, model.ReadConcern, model.ReadPreference)
+}
+
 func (r *indexResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = r.clusters.maskLogs(ctx)
+	defer func() { resp.Diagnostics = r.clusters.redactDiagnostics(resp.Diagnostics) }()
+
 	// Retrieve import ID and save to id attribute, parse it and set it has the state of the resource to import
 	id, err := parseIndexId(req.ID)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Invalid id format. Should be <database>.<collection>.<index_name>.",
+			"Invalid id format. Should be [<cluster>:]<database>.<collection>.<index_name>.",
 			"An unexpected error occurred when creating index. "+
 				"If the error is not clear, please contact the provider developers.\n\n"+
 				"Error: "+err.Error(),
@@ -541,6 +897,9 @@ func (r *indexResource) ImportState(ctx context.Context, req resource.ImportStat
 		return
 	}
 
+	if id.cluster != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster"), id.cluster)...)
+	}
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("database"), id.database)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("collection"), id.collection)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), id.indexName)...)
@@ -1,9 +1,21 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestAccIndexResource(t *testing.T) {
@@ -128,3 +140,625 @@ resource "mongodb_index" "partial_filter_test" {
 		},
 	})
 }
+
+func TestAccIndexResourceText(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing of a compound text index
+			{
+				Config: providerConfig + `
+resource "mongodb_index" "text_test" {
+  database   = "test"
+  collection = "test"
+  name       = "text_idx"
+  keys = [
+    {
+      "field" : "category"
+      "type" : "asc"
+    },
+    {
+      "field" : "body"
+      "type" : "text"
+    },
+    {
+      "field" : "title"
+      "type" : "text"
+    }
+  ]
+  weights = {
+    "title" : 10
+  }
+  default_language = "french"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "keys.#", "3"),
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "keys.0.field", "category"),
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "keys.1.field", "body"),
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "keys.1.type", "text"),
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "keys.2.field", "title"),
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "keys.2.type", "text"),
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "weights.%", "1"),
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "weights.title", "10"),
+					resource.TestCheckResourceAttr("mongodb_index.text_test", "default_language", "french"),
+					resource.TestCheckNoResourceAttr("mongodb_index.text_test", "language_override"),
+					resource.TestCheckNoResourceAttr("mongodb_index.text_test", "text_index_version"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mongodb_index.text_test",
+				ImportStateId:     "test.test.text_idx",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccIndexResourceUpdateInPlace(t *testing.T) {
+	config := func(expireAfterSeconds int, unique bool) string {
+		return providerConfig + fmt.Sprintf(`
+resource "mongodb_index" "update_test" {
+  database   = "test"
+  collection = "update_test"
+  name       = "update_idx"
+  keys = [
+    {
+      "field" : "created_at"
+      "type" : "asc"
+    }
+  ]
+  expire_after_seconds = %d
+  unique               = %t
+}
+`, expireAfterSeconds, unique)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(3600, false),
+				Check:  resource.TestCheckResourceAttr("mongodb_index.update_test", "expire_after_seconds", "3600"),
+			},
+			// The ttl and uniqueness are changed with collMod, without replacing the index
+			{
+				Config: config(7200, true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("mongodb_index.update_test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.update_test", "expire_after_seconds", "7200"),
+					resource.TestCheckResourceAttr("mongodb_index.update_test", "unique", "true"),
+				),
+			},
+			// An index is replaced when it stops being unique
+			{
+				Config: config(7200, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("mongodb_index.update_test", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccIndexResourceHidden(t *testing.T) {
+	config := func(hidden bool) string {
+		return providerConfig + fmt.Sprintf(`
+resource "mongodb_index" "hidden_test" {
+  database   = "test"
+  collection = "hidden_test"
+  name       = "hidden_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+  hidden = %t
+}
+`, hidden)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(true),
+				Check:  resource.TestCheckResourceAttr("mongodb_index.hidden_test", "hidden", "true"),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mongodb_index.hidden_test",
+				ImportStateId:     "test.hidden_test.hidden_idx",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// The index is unhidden with collMod, without replacing it
+			{
+				Config: config(false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("mongodb_index.hidden_test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("mongodb_index.hidden_test", "hidden", "false"),
+			},
+		},
+	})
+}
+
+func TestAccIndexResourceOptionsImport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `
+resource "mongodb_index" "collation_test" {
+  database   = "test"
+  collection = "options_test"
+  name       = "collation_idx"
+  keys = [
+    {
+      "field" : "name"
+      "type" : "asc"
+    }
+  ]
+  collation = {
+    locale   = "fr"
+    strength = 2
+  }
+  background = true
+}
+
+resource "mongodb_index" "wildcard_test" {
+  database   = "test"
+  collection = "options_test"
+  name       = "wildcard_idx"
+  keys = [
+    {
+      "field" : "$**"
+      "type" : "asc"
+    }
+  ]
+  wildcard_projection = {
+    "attributes" : 1
+  }
+}
+
+resource "mongodb_index" "locale_test" {
+  database   = "test"
+  collection = "options_test"
+  name       = "locale_idx"
+  keys = [
+    {
+      "field" : "title"
+      "type" : "asc"
+    }
+  ]
+  collation = {
+    locale = "th"
+  }
+}
+
+resource "mongodb_index" "simple_test" {
+  database   = "test"
+  collection = "options_test"
+  name       = "simple_idx"
+  keys = [
+    {
+      "field" : "code"
+      "type" : "asc"
+    }
+  ]
+  collation = {
+    locale = "simple"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.collation_test", "collation.locale", "fr"),
+					resource.TestCheckResourceAttr("mongodb_index.collation_test", "collation.strength", "2"),
+					resource.TestCheckNoResourceAttr("mongodb_index.collation_test", "collation.case_first"),
+					resource.TestCheckResourceAttr("mongodb_index.collation_test", "background", "true"),
+					resource.TestCheckResourceAttr("mongodb_index.wildcard_test", "wildcard_projection.attributes", "1"),
+					resource.TestCheckNoResourceAttr("mongodb_index.locale_test", "collation.alternate"),
+					resource.TestCheckResourceAttr("mongodb_index.simple_test", "collation.locale", "simple"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "mongodb_index.collation_test",
+				ImportStateId:     "test.options_test.collation_idx",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "mongodb_index.wildcard_test",
+				ImportStateId:     "test.options_test.wildcard_idx",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// The defaults of the locale are not imported as declared options
+			{
+				ResourceName:      "mongodb_index.locale_test",
+				ImportStateId:     "test.options_test.locale_idx",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccIndexResourceDroppedOutsideTerraform(t *testing.T) {
+	config := func(options string) string {
+		return providerConfig + `
+resource "mongodb_index" "dropped_test" {
+  database   = "test"
+  collection = "dropped_test"
+  name       = "dropped_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+  ` + options + `
+}
+`
+	}
+	drop := func(dropIndex bool) func() {
+		return func() {
+			ctx := context.Background()
+			client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			defer func() { _ = client.Disconnect(ctx) }()
+			collection := client.Database("test").Collection("dropped_test")
+			if dropIndex {
+				_, err = collection.Indexes().DropOne(ctx, "dropped_idx")
+			} else {
+				err = collection.Drop(ctx)
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+	}
+	expectCreate := resource.ConfigPlanChecks{
+		PreApply: []plancheck.PlanCheck{
+			plancheck.ExpectResourceAction("mongodb_index.dropped_test", plancheck.ResourceActionCreate),
+		},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+			},
+			// The dropped index is removed from the state, then created again
+			{
+				PreConfig:        drop(true),
+				Config:           config(""),
+				ConfigPlanChecks: expectCreate,
+			},
+			// Same when its collection is dropped
+			{
+				PreConfig:        drop(false),
+				Config:           config("fail_on_missing = false"),
+				ConfigPlanChecks: expectCreate,
+			},
+			{
+				Config: config("fail_on_missing = true"),
+			},
+			// The refresh fails when fail_on_missing is set
+			{
+				PreConfig:   drop(false),
+				Config:      config("fail_on_missing = true"),
+				ExpectError: regexp.MustCompile("Unable to find index with name dropped_idx"),
+			},
+		},
+	})
+}
+
+// Requires a mongod started with TLS enabled, listening on MONGODB_TLS_URL with a certificate
+// signed by the CA in MONGODB_TLS_CA_FILE.
+func TestAccIndexResourceOverTls(t *testing.T) {
+	url := os.Getenv("MONGODB_TLS_URL")
+	caFile := os.Getenv("MONGODB_TLS_CA_FILE")
+	if url == "" || caFile == "" {
+		t.Skip("MONGODB_TLS_URL and MONGODB_TLS_CA_FILE must be set to test TLS connections")
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "mongodb" {
+  url = "` + url + `"
+  tls = {
+    ca_certificate_file = "` + caFile + `"
+  }
+}
+
+resource "mongodb_index" "tls_test" {
+  database   = "test"
+  collection = "test"
+  name       = "tls_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.tls_test", "name", "tls_idx"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIndexResourceWithConcerns(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing with the concerns of the provider
+			{
+				Config: `
+provider "mongodb" {
+  url = "mongodb://localhost"
+  write_concern = {
+    w        = "majority"
+    j        = true
+    wtimeout = "30s"
+  }
+  read_preference = {
+    mode = "primary"
+  }
+}
+
+resource "mongodb_index" "concerns_test" {
+  database   = "test"
+  collection = "test"
+  name       = "concerns_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.concerns_test", "name", "concerns_idx"),
+					resource.TestCheckNoResourceAttr("mongodb_index.concerns_test", "write_concern"),
+				),
+			},
+			// Update testing: the concerns of the resource are changed in place
+			{
+				Config: `
+provider "mongodb" {
+  url = "mongodb://localhost"
+  write_concern = {
+    w        = "majority"
+    j        = true
+    wtimeout = "30s"
+  }
+  read_preference = {
+    mode = "primary"
+  }
+}
+
+resource "mongodb_index" "concerns_test" {
+  database   = "test"
+  collection = "test"
+  name       = "concerns_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+  write_concern = {
+    w = "1"
+  }
+  read_concern = {
+    level = "local"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.concerns_test", "write_concern.w", "1"),
+					resource.TestCheckResourceAttr("mongodb_index.concerns_test", "read_concern.level", "local"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIndexResourceThroughSshTunnel(t *testing.T) {
+	server := newTestSshServer(t)
+	host, port, err := net.SplitHostPort(server.address)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "mongodb" {
+  url = "mongodb://localhost"
+  ssh_tunnel = {
+    host        = "` + host + `"
+    port        = ` + port + `
+    user        = "terraform"
+    private_key = <<EOT
+` + server.clientKey + `EOT
+    known_hosts = <<EOT
+` + server.knownHosts + `EOT
+  }
+}
+
+resource "mongodb_index" "ssh_tunnel_test" {
+  database   = "test"
+  collection = "test"
+  name       = "ssh_tunnel_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.ssh_tunnel_test", "name", "ssh_tunnel_idx"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIndexResourceInNamedCluster(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "mongodb" {
+  clusters = {
+    local = {
+      url = "mongodb://localhost"
+    }
+  }
+}
+
+resource "mongodb_index" "cluster_test" {
+  cluster    = "local"
+  database   = "test"
+  collection = "test"
+  name       = "cluster_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.cluster_test", "cluster", "local"),
+					resource.TestCheckResourceAttr("mongodb_index.cluster_test", "name", "cluster_idx"),
+				),
+			},
+			{
+				ResourceName:      "mongodb_index.cluster_test",
+				ImportStateId:     "local:test.test.cluster_idx",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccIndexResourceReadOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "mongodb" {
+  url       = "mongodb://localhost"
+  read_only = true
+}
+
+resource "mongodb_index" "read_only_test" {
+  database   = "test"
+  collection = "test"
+  name       = "read_only_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+}
+`,
+				ExpectError: regexp.MustCompile("MongoDB Provider Is Read-Only"),
+			},
+		},
+	})
+}
+
+func TestAccIndexResourcePreview(t *testing.T) {
+	previewFile := filepath.Join(t.TempDir(), "preview.js")
+	config := `
+provider "mongodb" {
+  url                 = "mongodb://localhost"
+  preview_output_file = "` + previewFile + `"
+}
+
+resource "mongodb_index" "preview_test" {
+  database   = "test"
+  collection = "preview_test"
+  name       = "preview_idx"
+  keys = [
+    {
+      "field" : "field1"
+      "type" : "asc"
+    }
+  ]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				// The script is not run, so the refresh following the apply removes the index
+				// from the state: only the provider that recorded its creation keeps it.
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("mongodb_index.preview_test", "name", "preview_idx"),
+					func(_ *terraform.State) error {
+						content, err := os.ReadFile(previewFile)
+						if err != nil {
+							return err
+						}
+						expected := `db.getSiblingDB("test").runCommand(EJSON.deserialize({"createIndexes":"preview_test"`
+						if !strings.Contains(string(content), expected) {
+							return fmt.Errorf("expected %s in %s", expected, content)
+						}
+						return nil
+					},
+				),
+			},
+			// The index missing from the deployment is planned to be created again
+			{
+				Config:             config,
+				ExpectNonEmptyPlan: true,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("mongodb_index.preview_test", plancheck.ResourceActionCreate),
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"sync"
+)
+
+// operationLimits caps the operations changing the deployment that run at the same time.
+// Zero means no limit.
+type operationLimits struct {
+	total         int64
+	perCollection int64
+}
+
+// operationLimiter queues the operations changing the deployment, such as index builds,
+// so that they do not run all at once. It is shared by the resources using the same client.
+type operationLimiter struct {
+	// total holds a token per running operation, nil when unlimited.
+	total         chan struct{}
+	perCollection int64
+
+	mu          sync.Mutex
+	collections map[string]*collectionSemaphore
+}
+
+// collectionSemaphore holds a token per running operation on a collection.
+type collectionSemaphore struct {
+	tokens chan struct{}
+	// users counts the operations holding or waiting for a token, so that the semaphore
+	// is dropped once unused.
+	users int
+}
+
+func newOperationLimiter(limits operationLimits) *operationLimiter {
+	l := &operationLimiter{perCollection: limits.perCollection, collections: map[string]*collectionSemaphore{}}
+	if limits.total > 0 {
+		l.total = make(chan struct{}, limits.total)
+	}
+	return l
+}
+
+// acquire waits until an operation on the namespace can run, or the context is done.
+// The returned function must be called once the operation is over.
+func (l *operationLimiter) acquire(ctx context.Context, namespace string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// Wait for the collection first, so that an operation waiting for its collection
+	// does not hold a token of the total limit.
+	releaseCollection, err := l.acquireCollection(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if l.total == nil {
+		return releaseCollection, nil
+	}
+
+	select {
+	case l.total <- struct{}{}:
+		return func() {
+			<-l.total
+			releaseCollection()
+		}, nil
+	case <-ctx.Done():
+		releaseCollection()
+		return nil, ctx.Err()
+	}
+}
+
+func (l *operationLimiter) acquireCollection(ctx context.Context, namespace string) (func(), error) {
+	if l.perCollection <= 0 {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	semaphore, ok := l.collections[namespace]
+	if !ok {
+		semaphore = &collectionSemaphore{tokens: make(chan struct{}, l.perCollection)}
+		l.collections[namespace] = semaphore
+	}
+	semaphore.users++
+	l.mu.Unlock()
+
+	done := func() {
+		l.mu.Lock()
+		semaphore.users--
+		if semaphore.users == 0 {
+			delete(l.collections, namespace)
+		}
+		l.mu.Unlock()
+	}
+
+	select {
+	case semaphore.tokens <- struct{}{}:
+		return func() {
+			<-semaphore.tokens
+			done()
+		}, nil
+	case <-ctx.Done():
+		done()
+		return nil, ctx.Err()
+	}
+}
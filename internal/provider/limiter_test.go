@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOperationLimiterTotal(t *testing.T) {
+	limiter := newOperationLimiter(operationLimits{total: 1})
+
+	release, err := limiter.acquire(context.Background(), "db.first")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx, "db.second"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	release()
+	release, err = limiter.acquire(context.Background(), "db.second")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	release()
+}
+
+func TestOperationLimiterPerCollection(t *testing.T) {
+	limiter := newOperationLimiter(operationLimits{perCollection: 1})
+
+	release, err := limiter.acquire(context.Background(), "db.first")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	other, err := limiter.acquire(context.Background(), "db.second")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	other()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx, "db.first"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		release, err := limiter.acquire(context.Background(), "db.first")
+		if err == nil {
+			release()
+		}
+		close(waited)
+	}()
+	release()
+	<-waited
+
+	if len(limiter.collections) != 0 {
+		t.Fatalf("Expected the unused collections to be dropped, got %d", len(limiter.collections))
+	}
+}
+
+func TestOperationLimiterWaitingForCollectionHoldsNoTotalToken(t *testing.T) {
+	limiter := newOperationLimiter(operationLimits{total: 2, perCollection: 1})
+
+	release, err := limiter.acquire(context.Background(), "db.first")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	go func() {
+		_, _ = limiter.acquire(ctx, "db.first")
+	}()
+
+	other, err := limiter.acquire(context.Background(), "db.second")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	other()
+}
+
+func TestOperationLimiterUnlimited(t *testing.T) {
+	var limiter *operationLimiter
+	release, err := limiter.acquire(context.Background(), "db.first")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	release()
+
+	limiter = newOperationLimiter(operationLimits{})
+	for i := 0; i < 10; i++ {
+		if _, err := limiter.acquire(context.Background(), "db.first"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
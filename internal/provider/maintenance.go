@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Days that can be set in the days attribute of a maintenance window.
+var maintenanceWindowDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// maintenanceWindowModel maps a window of the maintenance_windows attribute.
+type maintenanceWindowModel struct {
+	Days     types.List   `tfsdk:"days"`
+	Start    types.String `tfsdk:"start"`
+	End      types.String `tfsdk:"end"`
+	TimeZone types.String `tfsdk:"time_zone"`
+}
+
+// maintenanceWindow is a daily time range during which the resources can be changed.
+type maintenanceWindow struct {
+	// days the window opens on, every day when empty.
+	days []time.Weekday
+	// The window closes the next day when end is not after start.
+	start    timeOfDay
+	end      timeOfDay
+	location *time.Location
+}
+
+// timeOfDay is a wall clock time, such as 22:30.
+type timeOfDay struct {
+	hour   int
+	minute int
+}
+
+// before returns whether t comes before other in the day.
+func (t timeOfDay) before(other timeOfDay) bool {
+	return t.hour < other.hour || (t.hour == other.hour && t.minute < other.minute)
+}
+
+// maintenanceSchedule refuses the operations writing to the deployments outside of the
+// maintenance windows.
+type maintenanceSchedule struct {
+	windows []maintenanceWindow
+	// now returns the current time, replaced by the tests.
+	now func() time.Time
+}
+
+// maintenanceWindows returns the maintenance windows of the configuration, reporting
+// the values that are not known yet in unknown.
+func (m *mongodbProviderModel) maintenanceWindows(ctx context.Context, unknown *diag.Diagnostics) ([]maintenanceWindowModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if m.MaintenanceWindows.IsNull() || m.MaintenanceWindows.IsUnknown() {
+		return nil, diags
+	}
+
+	known := true
+	for i, value := range m.MaintenanceWindows.Elements() {
+		if value.IsUnknown() {
+			addUnknownAttributeError(unknown, path.Root("maintenance_windows").AtListIndex(i), "Maintenance Window", "")
+			known = false
+		}
+	}
+	if !known {
+		return nil, diags
+	}
+
+	var windows []maintenanceWindowModel
+	diags.Append(m.MaintenanceWindows.ElementsAs(ctx, &windows, false)...)
+	for i, window := range windows {
+		addUnknownNestedAttributeErrors(unknown, path.Root("maintenance_windows").AtListIndex(i), "Maintenance Window", []nestedAttributeValue{
+			{"days", window.Days},
+			{"start", window.Start},
+			{"end", window.End},
+			{"time_zone", window.TimeZone},
+		})
+	}
+	return windows, diags
+}
+
+// newMaintenanceSchedule parses the maintenance windows of the configuration. It
+// returns nil when there are none.
+func newMaintenanceSchedule(ctx context.Context, models []maintenanceWindowModel) (*maintenanceSchedule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if len(models) == 0 {
+		return nil, diags
+	}
+
+	schedule := &maintenanceSchedule{now: time.Now}
+	for i, model := range models {
+		attributePath := path.Root("maintenance_windows").AtListIndex(i)
+		window := maintenanceWindow{location: time.UTC}
+
+		var days []string
+		if !model.Days.IsNull() {
+			diags.Append(model.Days.ElementsAs(ctx, &days, false)...)
+		}
+		for _, day := range days {
+			window.days = append(window.days, parseWeekday(day))
+		}
+
+		var err error
+		if window.start, err = parseTimeOfDay(model.Start.ValueString()); err != nil {
+			diags.AddAttributeError(attributePath.AtName("start"), "Invalid Maintenance Window Start", "Error: "+err.Error())
+		}
+		if window.end, err = parseTimeOfDay(model.End.ValueString()); err != nil {
+			diags.AddAttributeError(attributePath.AtName("end"), "Invalid Maintenance Window End", "Error: "+err.Error())
+		}
+		if !diags.HasError() && window.start == window.end {
+			diags.AddAttributeError(
+				attributePath.AtName("end"),
+				"Invalid Maintenance Window End",
+				"The end of the maintenance window must differ from its start.",
+			)
+		}
+		if timeZone := model.TimeZone.ValueString(); timeZone != "" {
+			if window.location, err = time.LoadLocation(timeZone); err != nil {
+				diags.AddAttributeError(
+					attributePath.AtName("time_zone"),
+					"Invalid Maintenance Window Time Zone",
+					"The time_zone must be a name of the IANA time zone database, such as Europe/Paris.\n\n"+
+						"Error: "+err.Error(),
+				)
+			}
+		}
+		schedule.windows = append(schedule.windows, window)
+	}
+
+	if diags.HasError() {
+		return nil, diags
+	}
+	return schedule, diags
+}
+
+// parseWeekday returns the day named by one of maintenanceWindowDays.
+func parseWeekday(day string) time.Weekday {
+	for i, name := range maintenanceWindowDays {
+		if strings.EqualFold(day, name) {
+			// The names start on monday, the weekdays on sunday.
+			return time.Weekday((i + 1) % 7)
+		}
+	}
+	return time.Sunday
+}
+
+// parseTimeOfDay parses a time of day such as 22:30.
+func parseTimeOfDay(value string) (timeOfDay, error) {
+	hours, minutes, found := strings.Cut(value, ":")
+	if !found {
+		return timeOfDay{}, errors.New("expected a time of day such as 22:30, got " + value)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 || h > 23 {
+		return timeOfDay{}, errors.New("expected hours between 00 and 23, got " + value)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 || len(minutes) != 2 {
+		return timeOfDay{}, errors.New("expected minutes between 00 and 59, got " + value)
+	}
+	return timeOfDay{hour: h, minute: m}, nil
+}
+
+// occurrence returns the start and end of the window opening on the day of date, in the
+// time zone of the window. The times are built from the wall clock, so that the window
+// keeps its hours on the days of daylight saving time changes.
+func (w maintenanceWindow) occurrence(date time.Time) (time.Time, time.Time) {
+	year, month, day := date.Date()
+	start := time.Date(year, month, day, w.start.hour, w.start.minute, 0, 0, w.location)
+	endDay := day
+	if !w.start.before(w.end) {
+		endDay++
+	}
+	end := time.Date(year, month, endDay, w.end.hour, w.end.minute, 0, 0, w.location)
+	return start, end
+}
+
+func (w maintenanceWindow) opensOn(day time.Weekday) bool {
+	if len(w.days) == 0 {
+		return true
+	}
+	for _, d := range w.days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// isOpen returns whether one of the windows is open at t.
+func (s *maintenanceSchedule) isOpen(t time.Time) bool {
+	for _, window := range s.windows {
+		local := t.In(window.location)
+		// A window opening the day before may still be open.
+		for _, date := range []time.Time{local, local.AddDate(0, 0, -1)} {
+			if !window.opensOn(date.Weekday()) {
+				continue
+			}
+			start, end := window.occurrence(date)
+			if !t.Before(start) && t.Before(end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// next returns the start and end of the first window opening after t.
+func (s *maintenanceSchedule) next(t time.Time) (time.Time, time.Time, *time.Location) {
+	var nextStart, nextEnd time.Time
+	var nextLocation *time.Location
+	for _, window := range s.windows {
+		local := t.In(window.location)
+		for offset := 0; offset <= 7; offset++ {
+			date := local.AddDate(0, 0, offset)
+			if !window.opensOn(date.Weekday()) {
+				continue
+			}
+			start, end := window.occurrence(date)
+			if start.After(t) && (nextStart.IsZero() || start.Before(nextStart)) {
+				nextStart, nextEnd, nextLocation = start, end, window.location
+			}
+		}
+	}
+	return nextStart, nextEnd, nextLocation
+}
+
+// checkMaintenanceWindow reports an error when no maintenance window is open, before
+// the operation of the resource sends any command.
+func (s *maintenanceSchedule) checkMaintenanceWindow(operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if s == nil {
+		return diags
+	}
+	now := s.now()
+	if s.isOpen(now) {
+		return diags
+	}
+
+	start, end, location := s.next(now)
+	diags.AddError(
+		"Outside MongoDB Maintenance Window",
+		"The provider refuses to "+operation+" resources outside of its maintenance_windows. "+
+			"The next window opens on "+start.Format("Monday 2006-01-02 15:04")+
+			" and closes on "+end.Format("Monday 2006-01-02 15:04")+" ("+location.String()+").\n\n"+
+			"Set the MONGODB_IGNORE_MAINTENANCE_WINDOWS environment variable to true to run the operation anyway.",
+	)
+	return diags
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// maintenanceWindowObjectType is the type of the elements of the maintenance_windows attribute.
+var maintenanceWindowObjectType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"days":      types.ListType{ElemType: types.StringType},
+	"start":     types.StringType,
+	"end":       types.StringType,
+	"time_zone": types.StringType,
+}}
+
+// newTestMaintenanceSchedule returns the schedule of the windows, whose clock returns now.
+func newTestMaintenanceSchedule(t *testing.T, now time.Time, windows ...maintenanceWindowModel) *maintenanceSchedule {
+	t.Helper()
+
+	schedule, diags := newMaintenanceSchedule(context.Background(), windows)
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	schedule.now = func() time.Time { return now }
+	return schedule
+}
+
+func testMaintenanceWindow(days []string, start string, end string, timeZone string) maintenanceWindowModel {
+	window := maintenanceWindowModel{
+		Days:     types.ListNull(types.StringType),
+		Start:    types.StringValue(start),
+		End:      types.StringValue(end),
+		TimeZone: types.StringNull(),
+	}
+	if days != nil {
+		values := make([]attr.Value, 0, len(days))
+		for _, day := range days {
+			values = append(values, types.StringValue(day))
+		}
+		window.Days = types.ListValueMust(types.StringType, values)
+	}
+	if timeZone != "" {
+		window.TimeZone = types.StringValue(timeZone)
+	}
+	return window
+}
+
+func TestMaintenanceWindowOpen(t *testing.T) {
+	// Saturday 2024-06-01 23:00 in Paris.
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("Time zone database unavailable: %v", err)
+	}
+	now := time.Date(2024, 6, 1, 23, 0, 0, 0, paris)
+	window := testMaintenanceWindow([]string{"saturday"}, "22:00", "06:00", "Europe/Paris")
+
+	schedule := newTestMaintenanceSchedule(t, now, window)
+	if diags := schedule.checkMaintenanceWindow("create"); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	// The window opened on saturday is still open on sunday morning.
+	schedule.now = func() time.Time { return time.Date(2024, 6, 2, 5, 59, 0, 0, paris) }
+	if diags := schedule.checkMaintenanceWindow("create"); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+}
+
+func TestMaintenanceWindowDaylightSavingTime(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("Time zone database unavailable: %v", err)
+	}
+	window := testMaintenanceWindow(nil, "22:00", "06:00", "Europe/Paris")
+
+	// Clocks go forward on sunday 2024-03-31, the window still opens at 22:00.
+	schedule := newTestMaintenanceSchedule(t, time.Date(2024, 3, 31, 22, 30, 0, 0, paris), window)
+	if diags := schedule.checkMaintenanceWindow("create"); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	// Clocks go back on sunday 2024-10-27, the window is still closed at 21:30.
+	schedule.now = func() time.Time { return time.Date(2024, 10, 27, 21, 30, 0, 0, paris) }
+	if diags := schedule.checkMaintenanceWindow("create"); !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+	schedule.now = func() time.Time { return time.Date(2024, 10, 27, 22, 0, 0, 0, paris) }
+	if diags := schedule.checkMaintenanceWindow("create"); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+}
+
+func TestMaintenanceWindowClosed(t *testing.T) {
+	// Monday 2024-06-03 10:00 UTC.
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	schedule := newTestMaintenanceSchedule(t, now,
+		testMaintenanceWindow([]string{"saturday", "sunday"}, "22:00", "06:00", ""),
+		testMaintenanceWindow([]string{"wednesday"}, "01:00", "03:00", ""),
+	)
+
+	diags := schedule.checkMaintenanceWindow("delete")
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+	expected := "The next window opens on Wednesday 2024-06-05 01:00 and closes on Wednesday 2024-06-05 03:00 (UTC)"
+	if !strings.Contains(diags[0].Detail(), expected) {
+		t.Fatalf("Expected %v, got %v", expected, diags[0].Detail())
+	}
+}
+
+func TestMaintenanceWindowEveryDay(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 30, 0, 0, time.UTC)
+	schedule := newTestMaintenanceSchedule(t, now, testMaintenanceWindow(nil, "12:00", "13:00", ""))
+
+	if diags := schedule.checkMaintenanceWindow("create"); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	schedule.now = func() time.Time { return time.Date(2024, 6, 3, 13, 0, 0, 0, time.UTC) }
+	diags := schedule.checkMaintenanceWindow("create")
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "Tuesday 2024-06-04 12:00") {
+		t.Fatalf("Expected the next window to open on tuesday, got %v", diags)
+	}
+}
+
+func TestNoMaintenanceSchedule(t *testing.T) {
+	var schedule *maintenanceSchedule
+	if diags := schedule.checkMaintenanceWindow("create"); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+}
+
+func TestNewMaintenanceScheduleInvalid(t *testing.T) {
+	cases := []maintenanceWindowModel{
+		testMaintenanceWindow(nil, "24:00", "06:00", ""),
+		testMaintenanceWindow(nil, "22:00", "6h", ""),
+		testMaintenanceWindow(nil, "22:00", "22:00", ""),
+		testMaintenanceWindow(nil, "22:00", "06:00", "Mars/Olympus_Mons"),
+	}
+
+	for _, window := range cases {
+		if _, diags := newMaintenanceSchedule(context.Background(), []maintenanceWindowModel{window}); !diags.HasError() {
+			t.Fatalf("Should have failed for %+v", window)
+		}
+	}
+}
+
+func TestMaintenanceWindowsUnknown(t *testing.T) {
+	window := types.ObjectValueMust(maintenanceWindowObjectType.AttrTypes, map[string]attr.Value{
+		"days":      types.ListNull(types.StringType),
+		"start":     types.StringUnknown(),
+		"end":       types.StringValue("06:00"),
+		"time_zone": types.StringNull(),
+	})
+	config := mongodbProviderModel{MaintenanceWindows: types.ListValueMust(maintenanceWindowObjectType, []attr.Value{window})}
+
+	var unknown diag.Diagnostics
+	if _, diags := config.maintenanceWindows(context.Background(), &unknown); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if !unknown.HasError() {
+		t.Fatalf("Expected the unknown start to be reported")
+	}
+
+	// Errors unrelated to unknown values are not reported as such, so that they are not deferred.
+	config.MaintenanceWindows = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("22:00")})
+	unknown = nil
+	if _, diags := config.maintenanceWindows(context.Background(), &unknown); !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+	if unknown.HasError() {
+		t.Fatalf("Unexpected unknown values: %v", unknown)
+	}
+}
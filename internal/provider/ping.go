@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// Deadline of the ping sent when the provider is configured, unless the server
+// selection timeout is set.
+const defaultPingTimeout = 10 * time.Second
+
+// connectionFailure is the kind of problem that prevents the provider from reaching the deployment.
+type connectionFailure int
+
+const (
+	serverSelectionFailure connectionFailure = iota
+	dnsFailure
+	tcpFailure
+	tlsFailure
+	authenticationFailure
+	sshTunnelFailure
+	proxyFailure
+	proxyAuthenticationFailure
+)
+
+// ping checks that a server matching the read preference of the provider answers, so
+// that a wrong connection setting is reported when the provider is configured.
+func (d *mongodbProviderData) ping(ctx context.Context) diag.Diagnostics {
+	timeout := defaultPingTimeout
+	d.mu.RLock()
+	if d.config.serverSelectionTimeout != nil {
+		timeout = *d.config.serverSelectionTimeout
+	}
+	d.mu.RUnlock()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := d.retryOnAuthenticationFailure(ctx, func() error {
+		return d.currentClient().Ping(ctx, d.concerns.readPreference)
+	})
+	if err == nil {
+		return nil
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.config.pingErrorDiagnostics(err)
+}
+
+// pingErrorDiagnostics reports the reason why the deployment could not be pinged.
+func (c *clientConfig) pingErrorDiagnostics(err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	title, hint := "No Suitable MongoDB Server", "No server matching the read preference answered before the deadline. "+
+		"Check that the replica_set and direct_connection settings match the deployment."
+	switch classifyConnectionError(err) {
+	case proxyAuthenticationFailure:
+		title, hint = "MongoDB Proxy Authentication Failed", "The proxy rejected the credentials. "+
+			"Check the username and password of the proxy configuration, or of the proxy url set in the environment."
+	case proxyFailure:
+		title, hint = "Unable to Connect Through Proxy", "The connection to the server could not be opened through the proxy. "+
+			"Check the type, host and port of the proxy configuration, or the proxy url set in the environment."
+	case sshTunnelFailure:
+		title, hint = "Unable to Open SSH Tunnel", "The SSH connection to the bastion host could not be established. "+
+			"Check the host, port, user, private key and known hosts of the ssh_tunnel configuration."
+	case dnsFailure:
+		title, hint = "Unable to Resolve MongoDB Host", "The host name could not be resolved. Check the host names of the connection settings."
+	case tcpFailure:
+		title, hint = "Unable to Reach MongoDB Server", "The connection to the server could not be opened. "+
+			"Check the hosts and ports of the connection settings, and that no firewall blocks the connection."
+	case tlsFailure:
+		title, hint = "MongoDB TLS Handshake Failed", "The TLS connection to the server could not be established. "+
+			"Check that TLS is enabled on the server and the tls configuration of the provider."
+	case authenticationFailure:
+		title, hint = "MongoDB Authentication Failed", "The server rejected the credentials. "+
+			"Check the username, password, auth_source and auth_mechanism settings."
+	}
+
+	diags.AddAttributeError(
+		c.deploymentPath(),
+		title,
+		"The provider could not connect to "+c.redactedDeployment()+". "+hint+"\n\n"+
+			"Set ping_on_configure to false to skip this check.\n\n"+
+			"Error: "+c.redact(describeError(err, c.configuredAuthMechanism())),
+	)
+	return diags
+}
+
+// classifyConnectionError returns the kind of problem behind an error of the driver.
+func classifyConnectionError(err error) connectionFailure {
+	causes := errorCauses(err)
+
+	for _, cause := range causes {
+		var proxyErr *proxyError
+		if errors.As(cause, &proxyErr) {
+			if proxyErr.authentication {
+				return proxyAuthenticationFailure
+			}
+			return proxyFailure
+		}
+	}
+	for _, cause := range causes {
+		var tunnelErr *sshTunnelError
+		if errors.As(cause, &tunnelErr) {
+			return sshTunnelFailure
+		}
+	}
+	for _, cause := range causes {
+		var dnsErr *net.DNSError
+		if errors.As(cause, &dnsErr) {
+			return dnsFailure
+		}
+	}
+	if isClientCertificateRejected(err) || isServerCertificateError(err) {
+		return tlsFailure
+	}
+	for _, cause := range causes {
+		var recordHeaderErr tls.RecordHeaderError
+		var alertErr tls.AlertError
+		if errors.As(cause, &recordHeaderErr) || errors.As(cause, &alertErr) {
+			return tlsFailure
+		}
+	}
+	if isAuthenticationError(err) {
+		return authenticationFailure
+	}
+	for _, cause := range causes {
+		var opErr *net.OpError
+		if errors.As(cause, &opErr) && opErr.Op == "dial" {
+			return tcpFailure
+		}
+	}
+
+	return serverSelectionFailure
+}
+
+// deploymentPath returns the path of the attribute describing the deployment.
+func (c *clientConfig) deploymentPath() path.Path {
+	switch {
+	case len(c.hosts) > 0:
+		return path.Root("hosts")
+	case c.srvHost != "":
+		return path.Root("srv_host")
+	case c.urlFromFile:
+		return path.Root("url_file")
+	default:
+		return path.Root("url")
+	}
+}
+
+// redactedDeployment describes the deployment without its credentials.
+func (c *clientConfig) redactedDeployment() string {
+	switch {
+	case len(c.hosts) > 0:
+		return strings.Join(c.hosts, ",")
+	case c.srvHost != "":
+		return "mongodb+srv://" + c.srvHost
+	default:
+		return redactUrl(c.url)
+	}
+}
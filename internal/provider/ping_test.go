@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestClassifyConnectionError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want connectionFailure
+	}{
+		{&net.DNSError{Err: "no such host", Name: "mongo.invalid", IsNotFound: true}, dnsFailure},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, tcpFailure},
+		{errors.New("remote error: tls: bad certificate"), tlsFailure},
+		{mongo.CommandError{Code: 18, Message: "Authentication failed."}, authenticationFailure},
+		{errors.New("server selection error: context deadline exceeded"), serverSelectionFailure},
+	}
+
+	for _, c := range cases {
+		if got := classifyConnectionError(c.err); got != c.want {
+			t.Fatalf("Expected %v for %v, got %v", c.want, c.err, got)
+		}
+	}
+}
+
+func TestPingUnreachableServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	address := listener.Addr().String()
+	if err := listener.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	timeout := time.Second
+	config := clientConfig{
+		url:                    "mongodb://user:secret@" + address + "/?directConnection=true",
+		serverSelectionTimeout: &timeout,
+	}
+	data, err := newProviderData(config, operationConcerns{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() { _ = data.release(context.Background()) }()
+
+	diags := data.ping(context.Background())
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+	withPath, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("url")) {
+		t.Fatalf("Expected an error on url, got %v", diags)
+	}
+	if diags[0].Summary() != "Unable to Reach MongoDB Server" {
+		t.Fatalf("Expected a TCP failure, got %v", diags[0].Summary())
+	}
+	if strings.Contains(diags[0].Detail(), "secret") {
+		t.Fatalf("Expected the password to be redacted, got %v", diags[0].Detail())
+	}
+}
+
+func TestDeploymentPath(t *testing.T) {
+	tests := []struct {
+		config clientConfig
+		want   path.Path
+	}{
+		{clientConfig{url: "mongodb://localhost"}, path.Root("url")},
+		{clientConfig{url: "mongodb://localhost", urlFromFile: true}, path.Root("url_file")},
+		{clientConfig{hosts: []string{"localhost"}}, path.Root("hosts")},
+		{clientConfig{srvHost: "cluster.example.com"}, path.Root("srv_host")},
+	}
+
+	for _, test := range tests {
+		if got := test.config.deploymentPath(); !got.Equal(test.want) {
+			t.Fatalf("Expected %v, got %v", test.want, got)
+		}
+	}
+}
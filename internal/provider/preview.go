@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// Written at the top of a new preview script.
+const previewScriptHeader = "// Commands recorded by the MongoDB Terraform provider in preview mode, they were not run.\n" +
+	"// Review them, then run this script with mongosh against the matching deployment.\n"
+
+// commandPreview appends the commands of the resources to a mongosh script instead of
+// sending them to the deployment, so that they can be reviewed and run separately.
+type commandPreview struct {
+	mu   sync.Mutex
+	file string
+	// created holds the indexes whose creation was recorded by this provider, which the
+	// deployment only lists once the script is run.
+	created map[indexId]bool
+}
+
+// recordCreatedIndex remembers that the command creating the index was recorded.
+func (p *commandPreview) recordCreatedIndex(id indexId) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.created == nil {
+		p.created = map[indexId]bool{}
+	}
+	p.created[id] = true
+}
+
+// createdIndex returns whether the command creating the index was recorded by this provider.
+func (p *commandPreview) createdIndex(id indexId) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.created[id]
+}
+
+// record appends the command, to be run against the database of the cluster, to the
+// script. It returns a warning telling that the command was not run.
+func (p *commandPreview) record(cluster string, database string, description string, command bson.D) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := p.append(cluster, database, description, command); err != nil {
+		diags.AddError(
+			"Unable to Record MongoDB Command",
+			"An unexpected error occurred when writing the command to the preview_output_file "+p.file+".\n\n"+
+				"Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	diags.AddWarning(
+		"MongoDB Command Not Run",
+		"The provider is in preview mode: the command to "+strings.ToLower(description[:1])+description[1:]+
+			" was written to "+p.file+" instead of being sent to the deployment. "+
+			"The state assumes that the script is run.",
+	)
+	return diags
+}
+
+func (p *commandPreview) append(cluster string, database string, description string, command bson.D) error {
+	// Canonical extended JSON keeps the exact BSON types of the command.
+	document, err := bson.MarshalExtJSON(command, true, false)
+	if err != nil {
+		return err
+	}
+	databaseName, err := json.Marshal(database)
+	if err != nil {
+		return err
+	}
+
+	var script strings.Builder
+	script.WriteString("\n// " + strings.ReplaceAll(description, "\n", " ") + "\n")
+	if cluster != "" {
+		script.WriteString("// Cluster: " + strings.ReplaceAll(cluster, "\n", " ") + "\n")
+	}
+	fmt.Fprintf(&script, "db.getSiblingDB(%s).runCommand(EJSON.deserialize(%s));\n", databaseName, document)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	file, err := os.OpenFile(p.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	content := script.String()
+	if info.Size() == 0 {
+		content = previewScriptHeader + content
+	}
+	if _, err := file.WriteString(content); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// createIndexesCommand returns the createIndexes command sent by the driver to create
+// the index.
+func createIndexesCommand(collection string, keys bson.D, opts *options.IndexOptions, wc *writeconcern.WriteConcern) bson.D {
+	command := bson.D{
+		{Key: "createIndexes", Value: collection},
+		{Key: "indexes", Value: bson.A{indexSpecification(keys, opts)}},
+	}
+	return appendWriteConcern(command, wc)
+}
+
+// dropIndexesCommand returns the dropIndexes command sent by the driver to drop the index.
+func dropIndexesCommand(collection string, name string, wc *writeconcern.WriteConcern) bson.D {
+	command := bson.D{
+		{Key: "dropIndexes", Value: collection},
+		{Key: "index", Value: name},
+	}
+	return appendWriteConcern(command, wc)
+}
+
+// collModIndexCommand returns the collMod command changing an option of the index in place.
+func collModIndexCommand(collection string, name string, option bson.E, wc *writeconcern.WriteConcern) bson.D {
+	command := bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "index", Value: bson.D{{Key: "name", Value: name}, option}},
+	}
+	return appendWriteConcern(command, wc)
+}
+
+// indexSpecification returns the document describing the index in a createIndexes
+// command, with the options in the order the driver sends them.
+func indexSpecification(keys bson.D, opts *options.IndexOptions) bson.D {
+	specification := bson.D{{Key: "key", Value: keys}}
+	if opts.Name != nil {
+		specification = append(specification, bson.E{Key: "name", Value: *opts.Name})
+	}
+
+	optional := []struct {
+		key   string
+		value interface{}
+		set   bool
+	}{
+		{"background", opts.Background, opts.Background != nil},
+		{"expireAfterSeconds", opts.ExpireAfterSeconds, opts.ExpireAfterSeconds != nil},
+		{"sparse", opts.Sparse, opts.Sparse != nil},
+		{"storageEngine", opts.StorageEngine, opts.StorageEngine != nil},
+		{"unique", opts.Unique, opts.Unique != nil},
+		{"v", opts.Version, opts.Version != nil},
+		{"default_language", opts.DefaultLanguage, opts.DefaultLanguage != nil},
+		{"language_override", opts.LanguageOverride, opts.LanguageOverride != nil},
+		{"textIndexVersion", opts.TextVersion, opts.TextVersion != nil},
+		{"weights", opts.Weights, opts.Weights != nil},
+		{"2dsphereIndexVersion", opts.SphereVersion, opts.SphereVersion != nil},
+		{"bits", opts.Bits, opts.Bits != nil},
+		{"max", opts.Max, opts.Max != nil},
+		{"min", opts.Min, opts.Min != nil},
+		{"bucketSize", opts.BucketSize, opts.BucketSize != nil},
+		{"partialFilterExpression", opts.PartialFilterExpression, opts.PartialFilterExpression != nil},
+		{"wildcardProjection", opts.WildcardProjection, opts.WildcardProjection != nil},
+		{"hidden", opts.Hidden, opts.Hidden != nil},
+	}
+	for _, option := range optional {
+		if option.set {
+			specification = append(specification, bson.E{Key: option.key, Value: option.value})
+		}
+	}
+	if opts.Collation != nil {
+		specification = append(specification, bson.E{Key: "collation", Value: opts.Collation.ToDocument()})
+	}
+	return specification
+}
+
+// appendWriteConcern adds the write concern to the command, when set.
+func appendWriteConcern(command bson.D, wc *writeconcern.WriteConcern) bson.D {
+	if wc == nil {
+		return command
+	}
+
+	document := bson.D{}
+	if wc.W != nil {
+		document = append(document, bson.E{Key: "w", Value: wc.W})
+	}
+	if wc.Journal != nil {
+		document = append(document, bson.E{Key: "j", Value: *wc.Journal})
+	}
+	if wc.WTimeout > 0 {
+		document = append(document, bson.E{Key: "wtimeout", Value: wc.WTimeout.Milliseconds()})
+	}
+	return append(command, bson.E{Key: "writeConcern", Value: document})
+}
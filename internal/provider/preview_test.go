@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func TestCreateIndexesCommand(t *testing.T) {
+	name := "ttl"
+	expireAfterSeconds := int32(3600)
+	keys := bson.D{{Key: "created_at", Value: 1}}
+	opts := &options.IndexOptions{Name: &name, ExpireAfterSeconds: &expireAfterSeconds}
+	wc := &writeconcern.WriteConcern{W: "majority", WTimeout: 5 * time.Second}
+
+	command, err := bson.MarshalExtJSON(createIndexesCommand("events", keys, opts, wc), true, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"createIndexes":"events","indexes":[{"key":{"created_at":{"$numberInt":"1"}},"name":"ttl","expireAfterSeconds":{"$numberInt":"3600"}}],` +
+		`"writeConcern":{"w":"majority","wtimeout":{"$numberLong":"5000"}}}`
+	if string(command) != expected {
+		t.Fatalf("Expected %v, got %v", expected, string(command))
+	}
+}
+
+func TestDropIndexesCommand(t *testing.T) {
+	command, err := bson.MarshalExtJSON(dropIndexesCommand("events", "ttl", nil), false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"dropIndexes":"events","index":"ttl"}`
+	if string(command) != expected {
+		t.Fatalf("Expected %v, got %v", expected, string(command))
+	}
+}
+
+func TestCollModIndexCommand(t *testing.T) {
+	command, err := bson.MarshalExtJSON(collModIndexCommand("events", "ttl", bson.E{Key: "expireAfterSeconds", Value: int32(7200)}, nil), false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `{"collMod":"events","index":{"name":"ttl","expireAfterSeconds":7200}}`
+	if string(command) != expected {
+		t.Fatalf("Expected %v, got %v", expected, string(command))
+	}
+}
+
+func TestCommandPreviewRecord(t *testing.T) {
+	preview := &commandPreview{file: filepath.Join(t.TempDir(), "preview.js")}
+
+	diags := preview.record("", "shop", "Drop index shop.orders.created_at", dropIndexesCommand("orders", "created_at", nil))
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if len(diags.Warnings()) != 1 {
+		t.Fatalf("Expected a warning, got %v", diags)
+	}
+	diags = preview.record("eu", "shop", "Drop index shop.orders.status", dropIndexesCommand("orders", "status", nil))
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	content, err := os.ReadFile(preview.file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	script := string(content)
+	if strings.Count(script, previewScriptHeader) != 1 {
+		t.Fatalf("Expected a single header, got %v", script)
+	}
+	expected := `db.getSiblingDB("shop").runCommand(EJSON.deserialize({"dropIndexes":"orders","index":"created_at"}));`
+	if !strings.Contains(script, expected) {
+		t.Fatalf("Expected %v in %v", expected, script)
+	}
+	if !strings.Contains(script, "// Cluster: eu\n") {
+		t.Fatalf("Expected the cluster to be named in %v", script)
+	}
+}
+
+func TestCommandPreviewRecordUnwritableFile(t *testing.T) {
+	preview := &commandPreview{file: filepath.Join(t.TempDir(), "missing", "preview.js")}
+
+	diags := preview.record("", "shop", "Drop index shop.orders.created_at", dropIndexesCommand("orders", "created_at", nil))
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestCommandPreviewCreatedIndex(t *testing.T) {
+	preview := &commandPreview{file: filepath.Join(t.TempDir(), "preview.js")}
+	id := indexId{database: "test", collection: "events", indexName: "ttl"}
+
+	if preview.createdIndex(id) {
+		t.Fatalf("Expected no created index")
+	}
+	preview.recordCreatedIndex(id)
+	if !preview.createdIndex(id) {
+		t.Fatalf("Expected %v to be created", id)
+	}
+	if other := (indexId{cluster: "eu", database: "test", collection: "events", indexName: "ttl"}); preview.createdIndex(other) {
+		t.Fatalf("Expected %v not to be created", other)
+	}
+}
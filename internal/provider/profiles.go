@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of the connection settings.
+const (
+	configurationSource = "the provider configuration"
+	environmentSource   = "the %s environment variable"
+	profileSource       = "the %q profile of %s"
+)
+
+// connectionProfiles is the content of the profiles file.
+type connectionProfiles struct {
+	Profiles map[string]connectionProfile `yaml:"profiles"`
+}
+
+// connectionProfile holds the connection settings of a named deployment.
+type connectionProfile struct {
+	Url           string                `yaml:"url"`
+	Username      string                `yaml:"username"`
+	Password      string                `yaml:"password"`
+	AuthSource    string                `yaml:"auth_source"`
+	AuthMechanism string                `yaml:"auth_mechanism"`
+	Tls           *connectionProfileTls `yaml:"tls"`
+}
+
+type connectionProfileTls struct {
+	CaCertificatePem      string `yaml:"ca_certificate_pem"`
+	CaCertificateFile     string `yaml:"ca_certificate_file"`
+	ClientCertificatePem  string `yaml:"client_certificate_pem"`
+	ClientCertificateFile string `yaml:"client_certificate_file"`
+	ClientKeyPem          string `yaml:"client_key_pem"`
+	ClientKeyFile         string `yaml:"client_key_file"`
+	ServerName            string `yaml:"server_name"`
+	InsecureSkipVerify    *bool  `yaml:"insecure_skip_verify"`
+}
+
+// defaultProfilesFile returns the path of the profiles file used when none is set,
+// ~/.config/terraform-mongodb/profiles.yaml unless XDG_CONFIG_HOME is set.
+func defaultProfilesFile() (string, error) {
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configDir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configDir, "terraform-mongodb", "profiles.yaml"), nil
+}
+
+// loadConnectionProfile returns the profile with the given name from the profiles file.
+func loadConnectionProfile(file string, name string) (*connectionProfile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles connectionProfiles
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profiles); err != nil {
+		return nil, fmt.Errorf("invalid profiles file %s: %w", file, err)
+	}
+
+	profile, ok := profiles.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles.Profiles))
+		for profileName := range profiles.Profiles {
+			names = append(names, profileName)
+		}
+		slices.Sort(names)
+		return nil, errors.New("profile " + name + " not found in " + file + ", available profiles: " + strings.Join(names, ", "))
+	}
+	return &profile, nil
+}
+
+// settingSources records where each connection setting comes from, by attribute path.
+type settingSources map[string]string
+
+// record records the source of a string attribute, set in the configuration or by the
+// environment variable.
+func (s settingSources) record(attributePath path.Path, value types.String, envVar string) {
+	switch {
+	case !value.IsNull():
+		s[attributePath.String()] = configurationSource
+	case envVar != "" && os.Getenv(envVar) != "":
+		s[attributePath.String()] = fmt.Sprintf(environmentSource, envVar)
+	}
+}
+
+// annotate adds the sources of the settings to the errors of diags.
+func (s settingSources) annotate(diags diag.Diagnostics) diag.Diagnostics {
+	if len(s) == 0 || !diags.HasError() {
+		return diags
+	}
+
+	attributes := make([]string, 0, len(s))
+	for attribute := range s {
+		attributes = append(attributes, attribute)
+	}
+	slices.Sort(attributes)
+	description := "\n\nThe connection settings come from:"
+	for _, attribute := range attributes {
+		description += "\n- " + attribute + ": " + s[attribute]
+	}
+
+	annotated := make(diag.Diagnostics, 0, len(diags))
+	for _, d := range diags {
+		if d.Severity() != diag.SeverityError {
+			annotated = append(annotated, d)
+			continue
+		}
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			annotated = append(annotated, diag.NewAttributeErrorDiagnostic(withPath.Path(), d.Summary(), d.Detail()+description))
+		} else {
+			annotated = append(annotated, diag.NewErrorDiagnostic(d.Summary(), d.Detail()+description))
+		}
+	}
+	return annotated
+}
+
+// applyProfile fills the url, credentials and TLS settings of the model that are set
+// neither in the configuration nor in the environment with the ones of the profile. The
+// TLS settings of the profile are only used without a tls block in the configuration.
+func (m *mongodbProviderModel) applyProfile(profile *connectionProfile, source string, sources settingSources) {
+	applyProfileString := func(attributePath path.Path, value *types.String, envVar string, profileValue string) {
+		if value.IsNull() && (envVar == "" || os.Getenv(envVar) == "") && profileValue != "" {
+			*value = types.StringValue(profileValue)
+			sources[attributePath.String()] = source
+		}
+	}
+
+	// The url of the profile does not replace the other ways to describe the deployment.
+	if m.UrlFile.IsNull() && os.Getenv("MONGODB_URL_FILE") == "" && m.Hosts.IsNull() && m.SrvHost.IsNull() {
+		applyProfileString(path.Root("url"), &m.Url, "MONGODB_URL", profile.Url)
+	}
+	applyProfileString(path.Root("username"), &m.Username, "MONGODB_USERNAME", profile.Username)
+	applyProfileString(path.Root("password"), &m.Password, "MONGODB_PASSWORD", profile.Password)
+	applyProfileString(path.Root("auth_source"), &m.AuthSource, "MONGODB_AUTH_SOURCE", profile.AuthSource)
+	applyProfileString(path.Root("auth_mechanism"), &m.AuthMechanism, "MONGODB_AUTH_MECHANISM", profile.AuthMechanism)
+
+	// The TLS settings of the profile are not mixed with the tls block of the configuration,
+	// whose attributes are checked for conflicts with each other only.
+	if profile.Tls == nil || m.Tls != nil {
+		return
+	}
+	m.Tls = &mongodbProviderTlsModel{}
+	tlsPath := path.Root("tls")
+	applyProfileString(tlsPath.AtName("ca_certificate_pem"), &m.Tls.CaCertificatePem, "", profile.Tls.CaCertificatePem)
+	applyProfileString(tlsPath.AtName("ca_certificate_file"), &m.Tls.CaCertificateFile, "", profile.Tls.CaCertificateFile)
+	applyProfileString(tlsPath.AtName("client_certificate_pem"), &m.Tls.ClientCertificatePem, "", profile.Tls.ClientCertificatePem)
+	applyProfileString(tlsPath.AtName("client_certificate_file"), &m.Tls.ClientCertificateFile, "", profile.Tls.ClientCertificateFile)
+	applyProfileString(tlsPath.AtName("client_key_pem"), &m.Tls.ClientKeyPem, "", profile.Tls.ClientKeyPem)
+	applyProfileString(tlsPath.AtName("client_key_file"), &m.Tls.ClientKeyFile, "", profile.Tls.ClientKeyFile)
+	applyProfileString(tlsPath.AtName("server_name"), &m.Tls.ServerName, "", profile.Tls.ServerName)
+	if m.Tls.InsecureSkipVerify.IsNull() && profile.Tls.InsecureSkipVerify != nil {
+		m.Tls.InsecureSkipVerify = types.BoolValue(*profile.Tls.InsecureSkipVerify)
+		sources[tlsPath.AtName("insecure_skip_verify").String()] = source
+	}
+}
+
+// resolveProfile records the sources of the settings of the model, then completes
+// them with the profile selected by the profile attribute or the MONGODB_PROFILE
+// environment variable.
+func (m *mongodbProviderModel) resolveProfile() (settingSources, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	sources := settingSources{}
+	sources.record(path.Root("url"), m.Url, "MONGODB_URL")
+	sources.record(path.Root("url_file"), m.UrlFile, "MONGODB_URL_FILE")
+	sources.record(path.Root("username"), m.Username, "MONGODB_USERNAME")
+	sources.record(path.Root("password"), m.Password, "MONGODB_PASSWORD")
+	sources.record(path.Root("auth_source"), m.AuthSource, "MONGODB_AUTH_SOURCE")
+	sources.record(path.Root("auth_mechanism"), m.AuthMechanism, "MONGODB_AUTH_MECHANISM")
+	if m.Tls != nil {
+		sources[path.Root("tls").String()] = configurationSource
+	}
+
+	name := stringValueOrEnv(m.Profile, "MONGODB_PROFILE")
+	if name == "" {
+		return sources, diags
+	}
+
+	file := stringValueOrEnv(m.ProfilesFile, "MONGODB_PROFILES_FILE")
+	if file == "" {
+		var err error
+		if file, err = defaultProfilesFile(); err != nil {
+			diags.AddAttributeError(
+				path.Root("profiles_file"),
+				"Unable to Locate MongoDB Profiles File",
+				"The default profiles file could not be located, set the profiles_file attribute.\n\n"+
+					"Error: "+err.Error(),
+			)
+			return sources, diags
+		}
+	}
+
+	profile, err := loadConnectionProfile(file, name)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("profile"),
+			"Unable to Load MongoDB Profile",
+			"An unexpected error occurred when loading the "+name+" profile.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return sources, diags
+	}
+
+	m.applyProfile(profile, fmt.Sprintf(profileSource, name, file), sources)
+	return sources, diags
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testProfiles = `
+profiles:
+  dev:
+    url: mongodb://localhost:27017
+  prod:
+    url: mongodb://mongo.example.com:27017
+    username: terraform
+    password: secret
+    auth_source: admin
+    tls:
+      ca_certificate_file: /etc/ssl/mongo/ca.pem
+`
+
+func writeTestProfiles(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "profiles.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return file
+}
+
+// newTestProviderModel returns a provider model whose attributes are all null.
+func newTestProviderModel() mongodbProviderModel {
+	return mongodbProviderModel{
+		Hosts:              types.ListNull(types.StringType),
+		CredentialCommand:  types.ListNull(types.StringType),
+		Compressors:        types.ListNull(types.StringType),
+		Clusters:           types.MapNull(clusterObjectType),
+		MaintenanceWindows: types.ListNull(maintenanceWindowObjectType),
+	}
+}
+
+func TestLoadConnectionProfile(t *testing.T) {
+	file := writeTestProfiles(t, testProfiles)
+
+	profile, err := loadConnectionProfile(file, "prod")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if profile.Url != "mongodb://mongo.example.com:27017" || profile.Username != "terraform" || profile.Tls == nil {
+		t.Fatalf("Unexpected profile %+v", profile)
+	}
+}
+
+func TestLoadConnectionProfileNotFound(t *testing.T) {
+	file := writeTestProfiles(t, testProfiles)
+
+	_, err := loadConnectionProfile(file, "staging")
+	if err == nil || !strings.Contains(err.Error(), "dev, prod") {
+		t.Fatalf("Expected the available profiles to be listed, got %v", err)
+	}
+}
+
+func TestLoadConnectionProfileUnknownSetting(t *testing.T) {
+	file := writeTestProfiles(t, "profiles:\n  dev:\n    uri: mongodb://localhost\n")
+
+	_, err := loadConnectionProfile(file, "dev")
+	if err == nil {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestResolveProfilePrecedence(t *testing.T) {
+	file := writeTestProfiles(t, testProfiles)
+	t.Setenv("MONGODB_USERNAME", "from-env")
+	t.Setenv("MONGODB_URL", "")
+	t.Setenv("MONGODB_PASSWORD", "")
+	t.Setenv("MONGODB_AUTH_SOURCE", "")
+
+	model := newTestProviderModel()
+	model.Profile = types.StringValue("prod")
+	model.ProfilesFile = types.StringValue(file)
+	model.AuthSource = types.StringValue("users")
+
+	sources, diags := model.resolveProfile()
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	if model.Url.ValueString() != "mongodb://mongo.example.com:27017" || model.Password.ValueString() != "secret" {
+		t.Fatalf("Expected the url and password of the profile, got %v %v", model.Url, model.Password)
+	}
+	if !model.Username.IsNull() {
+		t.Fatalf("Expected the username of the environment to be kept, got %v", model.Username)
+	}
+	if model.AuthSource.ValueString() != "users" {
+		t.Fatalf("Expected the auth source of the configuration to be kept, got %v", model.AuthSource)
+	}
+	if model.Tls == nil || model.Tls.CaCertificateFile.ValueString() != "/etc/ssl/mongo/ca.pem" {
+		t.Fatalf("Expected the TLS settings of the profile, got %+v", model.Tls)
+	}
+
+	want := map[string]string{
+		"url":                     `the "prod" profile of ` + file,
+		"username":                "the MONGODB_USERNAME environment variable",
+		"auth_source":             "the provider configuration",
+		"tls.ca_certificate_file": `the "prod" profile of ` + file,
+	}
+	for attribute, source := range want {
+		if sources[attribute] != source {
+			t.Fatalf("Expected %v to come from %v, got %v", attribute, source, sources[attribute])
+		}
+	}
+}
+
+func TestResolveProfileKeepsTlsBlock(t *testing.T) {
+	file := writeTestProfiles(t, testProfiles)
+
+	model := newTestProviderModel()
+	model.Profile = types.StringValue("prod")
+	model.ProfilesFile = types.StringValue(file)
+	model.Tls = &mongodbProviderTlsModel{CaCertificatePem: types.StringValue("-----BEGIN CERTIFICATE-----")}
+
+	if _, diags := model.resolveProfile(); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if !model.Tls.CaCertificateFile.IsNull() {
+		t.Fatalf("Expected the TLS settings of the profile to be ignored, got %v", model.Tls.CaCertificateFile)
+	}
+}
+
+func TestSettingSourcesAnnotate(t *testing.T) {
+	sources := settingSources{"url": `the "dev" profile of profiles.yaml`}
+	var diags diag.Diagnostics
+	diags.AddAttributeError(path.Root("url"), "Unable to Reach MongoDB Server", "Connection refused.")
+
+	annotated := sources.annotate(diags)
+	if !strings.Contains(annotated[0].Detail(), `- url: the "dev" profile of profiles.yaml`) {
+		t.Fatalf("Expected the sources of the settings, got %v", annotated[0].Detail())
+	}
+	withPath, ok := annotated[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("url")) {
+		t.Fatalf("Expected the path to be kept, got %v", annotated[0])
+	}
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// mongodbProviderData holds the client of a deployment, made available to the resources
+// through mongodbProviderClusters.
+type mongodbProviderData struct {
+	mu     sync.RWMutex
+	client *mongo.Client
+	// config is the settings the client was created with, and clientKey its key in the client cache.
+	config    clientConfig
+	clientKey string
+	// limiter queues the operations changing the deployment, shared with the other users of the client.
+	limiter *operationLimiter
+
+	// concerns applied to every database handle, unless overridden by the resource.
+	concerns operationConcerns
+	// version of the deployment, read on first use by serverVersion.
+	version []int32
+}
+
+// newProviderData returns the provider data using the client created with config,
+// taken from the client cache.
+func newProviderData(config clientConfig, concerns operationConcerns) (*mongodbProviderData, error) {
+	d := &mongodbProviderData{config: config, concerns: concerns}
+	if err := d.connect(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// connect acquires the client matching the configuration. The lock must be held,
+// unless the provider data is not shared yet.
+func (d *mongodbProviderData) connect() error {
+	opts, diags := d.config.clientOptions()
+	if diags.HasError() {
+		return diagnosticsError(diags)
+	}
+
+	clientKey := d.config.cacheKey()
+	client, limiter, err := clients.acquire(clientKey, opts, d.config.operationLimits())
+	if err != nil {
+		return err
+	}
+	d.client = client
+	d.limiter = limiter
+	d.clientKey = clientKey
+	return nil
+}
+
+// database returns a handle on the database, applying the concerns of the provider
+// overridden by the ones of the resource.
+func (d *mongodbProviderData) database(name string, overrides operationConcerns) *mongo.Database {
+	return d.currentClient().Database(name, d.concerns.override(overrides).databaseOptions())
+}
+
+func (d *mongodbProviderData) currentClient() *mongo.Client {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.client
+}
+
+// retryOnAuthenticationFailure runs the operation, and runs it once more with the
+// credentials returned again by the credential command when the server rejected them.
+func (d *mongodbProviderData) retryOnAuthenticationFailure(ctx context.Context, operation func() error) error {
+	d.mu.RLock()
+	failedClient := d.client
+	refreshable := len(d.config.credentialCommand) > 0
+	d.mu.RUnlock()
+
+	err := operation()
+	if err == nil || !refreshable || !isAuthenticationError(err) {
+		return err
+	}
+
+	if refreshErr := d.refreshCredentials(ctx, failedClient); refreshErr != nil {
+		return errors.Join(err, refreshErr)
+	}
+	return operation()
+}
+
+// runLimited waits until the limits of concurrent operations allow an operation on the
+// collection, then runs it like retryOnAuthenticationFailure.
+func (d *mongodbProviderData) runLimited(ctx context.Context, database string, collection string, operation func() error) error {
+	d.mu.RLock()
+	limiter := d.limiter
+	d.mu.RUnlock()
+
+	namespace := database + "." + collection
+	tflog.Debug(ctx, "Waiting for the limits of concurrent operations", map[string]interface{}{"namespace": namespace})
+	release, err := limiter.acquire(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("gave up waiting for the limits of concurrent operations on %s: %w", namespace, err)
+	}
+	defer release()
+
+	return d.retryOnAuthenticationFailure(ctx, operation)
+}
+
+// refreshCredentials runs the credential command again and replaces the client that
+// failed to authenticate, unless another operation already replaced it.
+func (d *mongodbProviderData) refreshCredentials(ctx context.Context, failedClient *mongo.Client) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client != failedClient {
+		return nil
+	}
+
+	config := d.config
+	if err := config.applyCredentialCommand(ctx); err != nil {
+		return err
+	}
+
+	previousConfig, previousClientKey := d.config, d.clientKey
+	d.config = config
+	if err := d.connect(); err != nil {
+		d.config = previousConfig
+		return err
+	}
+	return clients.release(ctx, previousClientKey)
+}
+
+// describeError describes an error of the driver, with the hints matching the
+// configuration of the client.
+func (d *mongodbProviderData) describeError(err error) string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return describeError(err, d.config.configuredAuthMechanism())
+}
+
+// release gives up the client of the provider data.
+func (d *mongodbProviderData) release(ctx context.Context) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return clients.release(ctx, d.clientKey)
+}
+
+// diagnosticsError converts the errors of diagnostics into an error.
+func diagnosticsError(diags diag.Diagnostics) error {
+	var errs []error
+	for _, d := range diags.Errors() {
+		errs = append(errs, errors.New(d.Summary()+": "+d.Detail()))
+	}
+	return errors.Join(errs...)
+}
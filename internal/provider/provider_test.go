@@ -1,8 +1,14 @@
 package provider
 
 import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 const (
@@ -22,3 +28,131 @@ var (
 		"mongodb": providerserver.NewProtocol6WithError(New("test")()),
 	}
 )
+
+// newTestProviderConfig returns a configuration of the provider where every attribute is
+// null, except the given ones.
+func newTestProviderConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+
+	schemaResp := &provider.SchemaResponse{}
+	New("test")().Schema(context.Background(), provider.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", schemaResp.Diagnostics)
+	}
+
+	objectType, ok := schemaResp.Schema.Type().TerraformType(context.Background()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("Expected an object type, got %T", schemaResp.Schema.Type().TerraformType(context.Background()))
+	}
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+
+	return tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(objectType, attributes),
+	}
+}
+
+func TestConfigureUnknownUrl(t *testing.T) {
+	config := newTestProviderConfig(t, map[string]tftypes.Value{
+		"url": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+
+	resp := &provider.ConfigureResponse{}
+	New("test")().Configure(context.Background(), provider.ConfigureRequest{Config: config}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatalf("Should have failed")
+	}
+	if resp.Deferred != nil {
+		t.Fatalf("Expected no deferral, got %v", resp.Deferred)
+	}
+}
+
+func TestConfigureUnknownUrlDeferred(t *testing.T) {
+	config := newTestProviderConfig(t, map[string]tftypes.Value{
+		"url": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})
+
+	req := provider.ConfigureRequest{
+		Config: config,
+		ClientCapabilities: provider.ConfigureProviderClientCapabilities{
+			DeferralAllowed: true,
+		},
+	}
+	resp := &provider.ConfigureResponse{}
+	New("test")().Configure(context.Background(), req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+	if resp.Deferred == nil || resp.Deferred.Reason != provider.DeferredReasonProviderConfigUnknown {
+		t.Fatalf("Expected %v, got %v", provider.DeferredReasonProviderConfigUnknown, resp.Deferred)
+	}
+	if resp.ResourceData != nil {
+		t.Fatalf("Expected no resource data, got %v", resp.ResourceData)
+	}
+}
+
+func TestConfigureNamedClusters(t *testing.T) {
+	clusterType, ok := clusterObjectType.TerraformType(context.Background()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("Expected an object type, got %T", clusterObjectType.TerraformType(context.Background()))
+	}
+	config := newTestProviderConfig(t, map[string]tftypes.Value{
+		"ping_on_configure": tftypes.NewValue(tftypes.Bool, false),
+		"clusters": tftypes.NewValue(tftypes.Map{ElementType: clusterType}, map[string]tftypes.Value{
+			"eu": tftypes.NewValue(clusterType, map[string]tftypes.Value{
+				"url":            tftypes.NewValue(tftypes.String, "mongodb://localhost:27017"),
+				"username":       tftypes.NewValue(tftypes.String, nil),
+				"password":       tftypes.NewValue(tftypes.String, nil),
+				"auth_source":    tftypes.NewValue(tftypes.String, nil),
+				"auth_mechanism": tftypes.NewValue(tftypes.String, nil),
+			}),
+		}),
+	})
+
+	resp := &provider.ConfigureResponse{}
+	New("test")().Configure(context.Background(), provider.ConfigureRequest{Config: config}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+
+	clusters, ok := resp.ResourceData.(*mongodbProviderClusters)
+	if !ok {
+		t.Fatalf("Expected *mongodbProviderClusters, got %T", resp.ResourceData)
+	}
+	defer func() { _ = clusters.release(context.Background()) }()
+	if clusters.defaultCluster != nil {
+		t.Fatalf("Expected no default cluster, got %v", clusters.defaultCluster)
+	}
+	if _, err := clusters.cluster("eu"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestConfigureReadOnlyFromEnv(t *testing.T) {
+	t.Setenv("MONGODB_READ_ONLY", "true")
+	config := newTestProviderConfig(t, map[string]tftypes.Value{
+		"url":               tftypes.NewValue(tftypes.String, "mongodb://localhost:27017"),
+		"ping_on_configure": tftypes.NewValue(tftypes.Bool, false),
+	})
+
+	resp := &provider.ConfigureResponse{}
+	New("test")().Configure(context.Background(), provider.ConfigureRequest{Config: config}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error: %v", resp.Diagnostics)
+	}
+
+	clusters, ok := resp.ResourceData.(*mongodbProviderClusters)
+	if !ok {
+		t.Fatalf("Expected *mongodbProviderClusters, got %T", resp.ResourceData)
+	}
+	defer func() { _ = clusters.release(context.Background()) }()
+	if !clusters.readOnly {
+		t.Fatalf("Expected the provider to be read-only")
+	}
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Replaces the secrets in diagnostics.
+const redactedValue = "REDACTED"
+
+var (
+	// Matches the user information of a url, such as a connection string or a proxy url.
+	urlCredentialsRegexp = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^@/\s"']*@`)
+	// Matches a PEM encoded private key.
+	pemPrivateKeyRegexp = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)
+)
+
+// redactUrl replaces the credentials of a connection string.
+func redactUrl(value string) string {
+	return urlCredentialsRegexp.ReplaceAllString(value, "${1}"+redactedValue+"@")
+}
+
+// redactSecrets hides the credentials of urls, the PEM private keys and the given
+// secrets in a message.
+func redactSecrets(message string, secrets []string) string {
+	message = redactUrl(message)
+	message = pemPrivateKeyRegexp.ReplaceAllString(message, redactedValue)
+
+	// Longer secrets first, so that a secret containing another one is fully hidden.
+	sorted := slices.Clone(secrets)
+	slices.SortFunc(sorted, func(a string, b string) int { return len(b) - len(a) })
+	for _, secret := range sorted {
+		if secret != "" {
+			message = strings.ReplaceAll(message, secret, redactedValue)
+		}
+	}
+	return message
+}
+
+// redactDiagnostics hides the secrets in the summary and detail of diagnostics.
+func redactDiagnostics(diags diag.Diagnostics, secrets []string) diag.Diagnostics {
+	if len(diags) == 0 {
+		return diags
+	}
+
+	redacted := make(diag.Diagnostics, 0, len(diags))
+	for _, d := range diags {
+		summary, detail := redactSecrets(d.Summary(), secrets), redactSecrets(d.Detail(), secrets)
+		withPath, hasPath := d.(diag.DiagnosticWithPath)
+		switch {
+		case d.Severity() == diag.SeverityError && hasPath:
+			redacted = append(redacted, diag.NewAttributeErrorDiagnostic(withPath.Path(), summary, detail))
+		case d.Severity() == diag.SeverityError:
+			redacted = append(redacted, diag.NewErrorDiagnostic(summary, detail))
+		case hasPath:
+			redacted = append(redacted, diag.NewAttributeWarningDiagnostic(withPath.Path(), summary, detail))
+		default:
+			redacted = append(redacted, diag.NewWarningDiagnostic(summary, detail))
+		}
+	}
+	return redacted
+}
+
+// maskLogs returns a context whose log messages and fields hide the same values as
+// redactSecrets.
+func maskLogs(ctx context.Context, secrets []string) context.Context {
+	ctx = tflog.MaskMessageRegexes(ctx, urlCredentialsRegexp, pemPrivateKeyRegexp)
+	ctx = tflog.MaskAllFieldValuesRegexes(ctx, urlCredentialsRegexp, pemPrivateKeyRegexp)
+
+	var nonEmpty []string
+	for _, secret := range secrets {
+		if secret != "" {
+			nonEmpty = append(nonEmpty, secret)
+		}
+	}
+	if len(nonEmpty) > 0 {
+		ctx = tflog.MaskMessageStrings(ctx, nonEmpty...)
+		ctx = tflog.MaskAllFieldValuesStrings(ctx, nonEmpty...)
+	}
+	return ctx
+}
+
+// secrets returns the secret values of the configuration.
+func (c *clientConfig) secrets() []string {
+	secrets := []string{c.password}
+	if c.url != "" {
+		if credentials := urlCredentialsRegexp.FindStringSubmatch(c.url); credentials != nil {
+			// The user information without the scheme and the trailing @.
+			userInfo := strings.TrimSuffix(strings.TrimPrefix(credentials[0], credentials[1]), "@")
+			if _, password, found := strings.Cut(userInfo, ":"); found {
+				secrets = append(secrets, password)
+				// Errors may also show the password once decoded.
+				if decoded, err := url.PathUnescape(password); err == nil && decoded != password {
+					secrets = append(secrets, decoded)
+				}
+			}
+		}
+	}
+	if c.tls != nil {
+		secrets = append(secrets, c.tls.clientKeyPem)
+	}
+	if c.sshTunnel != nil {
+		secrets = append(secrets, c.sshTunnel.privateKey, c.sshTunnel.privateKeyPassphrase)
+	}
+	if c.proxy != nil {
+		secrets = append(secrets, c.proxy.password)
+	}
+	return secrets
+}
+
+// redact hides the credentials of the configuration in a message.
+func (c *clientConfig) redact(message string) string {
+	return redactSecrets(message, c.secrets())
+}
+
+// secrets returns the secret values of the settings of the client.
+func (d *mongodbProviderData) secrets() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.config.secrets()
+}
+
+// secrets returns the secret values of the settings of every cluster.
+func (c *mongodbProviderClusters) secrets() []string {
+	if c == nil {
+		return nil
+	}
+
+	var secrets []string
+	if c.defaultCluster != nil {
+		secrets = append(secrets, c.defaultCluster.secrets()...)
+	}
+	for _, data := range c.named {
+		secrets = append(secrets, data.secrets()...)
+	}
+	return secrets
+}
+
+// redactDiagnostics hides the secrets of every cluster in diags.
+func (c *mongodbProviderClusters) redactDiagnostics(diags diag.Diagnostics) diag.Diagnostics {
+	return redactDiagnostics(diags, c.secrets())
+}
+
+// maskLogs returns a context whose logs hide the secrets of every cluster.
+func (c *mongodbProviderClusters) maskLogs(ctx context.Context) context.Context {
+	return maskLogs(ctx, c.secrets())
+}
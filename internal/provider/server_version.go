@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Minimum server versions of the index options.
+var hiddenIndexMinVersion = []int32{4, 4}
+var uniqueConversionMinVersion = []int32{6, 0}
+
+// Version assumed when the server refuses buildInfo because the Stable API is strict, as
+// the Stable API requires MongoDB 5.0 or later.
+var strictServerApiMinVersion = []int32{5, 0}
+
+// serverVersion returns the version of the deployment, such as [7 0 2 0]. It is asked
+// once per provider data.
+func (d *mongodbProviderData) serverVersion(ctx context.Context) ([]int32, error) {
+	d.mu.RLock()
+	version := d.version
+	d.mu.RUnlock()
+	if version != nil {
+		return version, nil
+	}
+
+	var buildInfo struct {
+		VersionArray []int32 `bson:"versionArray"`
+	}
+	err := d.retryOnAuthenticationFailure(ctx, func() error {
+		return d.currentClient().Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo)
+	})
+	switch {
+	case hasErrorCode(err, apiStrictErrorCode):
+		version = strictServerApiMinVersion
+	case err != nil:
+		return nil, err
+	default:
+		version = buildInfo.VersionArray
+	}
+
+	d.mu.Lock()
+	d.version = version
+	d.mu.Unlock()
+	return version, nil
+}
+
+// checkServerVersion reports an error on the attribute when the deployment is older than
+// the minimum version of the feature. The check is skipped when the version cannot be
+// read, the operations then report their own errors.
+func (d *mongodbProviderData) checkServerVersion(ctx context.Context, attribute path.Path, feature string, minimum []int32) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	version, err := d.serverVersion(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to read the version of the MongoDB server", map[string]interface{}{"error": err.Error()})
+		return diags
+	}
+	if versionAtLeast(version, minimum) {
+		return diags
+	}
+
+	diags.AddAttributeError(
+		attribute,
+		"Unsupported MongoDB Server Version",
+		fmt.Sprintf("%s require MongoDB %s or later, the server runs MongoDB %s.", feature, formatVersion(minimum), formatVersion(version)),
+	)
+	return diags
+}
+
+// versionAtLeast returns whether the version is the minimum one or a later one.
+func versionAtLeast(version []int32, minimum []int32) bool {
+	for i, part := range minimum {
+		if i >= len(version) {
+			return part == 0
+		}
+		if version[i] != part {
+			return version[i] > part
+		}
+	}
+	return true
+}
+
+// formatVersion formats the major, minor and patch numbers of the version.
+func formatVersion(version []int32) string {
+	if len(version) > 3 {
+		version = version[:3]
+	}
+	parts := make([]string, len(version))
+	for i, part := range version {
+		parts[i] = fmt.Sprint(part)
+	}
+	return strings.Join(parts, ".")
+}
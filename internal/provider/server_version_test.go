@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version []int32
+		minimum []int32
+		want    bool
+	}{
+		{[]int32{4, 4, 0, 0}, []int32{4, 4}, true},
+		{[]int32{7, 0, 2, 0}, []int32{4, 4}, true},
+		{[]int32{4, 2, 24, 0}, []int32{4, 4}, false},
+		{[]int32{3, 6, 0, 0}, []int32{4, 4}, false},
+		{[]int32{5}, []int32{5, 0}, true},
+	}
+
+	for _, test := range tests {
+		if got := versionAtLeast(test.version, test.minimum); got != test.want {
+			t.Fatalf("Expected %v for %v at least %v, got %v", test.want, test.version, test.minimum, got)
+		}
+	}
+}
+
+func TestFormatVersion(t *testing.T) {
+	if version := formatVersion([]int32{7, 0, 2, 0}); version != "7.0.2" {
+		t.Fatalf("Expected %v, got %v", "7.0.2", version)
+	}
+	if version := formatVersion([]int32{4, 4}); version != "4.4" {
+		t.Fatalf("Expected %v, got %v", "4.4", version)
+	}
+}
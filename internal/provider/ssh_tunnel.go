@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Port of the bastion host, unless set.
+const defaultSshPort = 22
+
+// Deadline of the SSH handshake with the bastion host.
+const sshHandshakeTimeout = 30 * time.Second
+
+// clientSshTunnelConfig holds the settings of the SSH tunnel through which the client
+// reaches the deployment.
+type clientSshTunnelConfig struct {
+	host                 string
+	port                 int64
+	user                 string
+	privateKey           string
+	privateKeyFile       string
+	privateKeyPassphrase string
+	knownHosts           string
+	knownHostsFile       string
+}
+
+// sshTunnelError is returned when the SSH tunnel cannot be opened.
+type sshTunnelError struct {
+	err error
+}
+
+func (e *sshTunnelError) Error() string {
+	return "unable to open the SSH tunnel: " + e.err.Error()
+}
+
+func (e *sshTunnelError) Unwrap() error {
+	return e.err
+}
+
+// dialer returns the dialer routing the connections of the client through the tunnel.
+// The tunnel is opened by the first connection.
+func (c *clientSshTunnelConfig) dialer() (*sshTunnel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	attributePath := path.Root("ssh_tunnel")
+
+	keyPem, pemDiags := readPem(c.privateKey, c.privateKeyFile, attributePath.AtName("private_key_file"))
+	diags.Append(pemDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	if len(keyPem) == 0 {
+		diags.AddAttributeError(
+			attributePath,
+			"Missing SSH Private Key",
+			"Set the private_key or private_key_file of the ssh_tunnel configuration.",
+		)
+		return nil, diags
+	}
+
+	var signer ssh.Signer
+	var err error
+	if c.privateKeyPassphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(keyPem, []byte(c.privateKeyPassphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(keyPem)
+	}
+	if err != nil {
+		diags.AddAttributeError(
+			attributePath.AtName(pemAttributeName(c.privateKey, "private_key", "private_key_file")),
+			"Invalid SSH Private Key",
+			"Error: "+err.Error(),
+		)
+		return nil, diags
+	}
+
+	hostKeyCallback, err := c.hostKeyCallback()
+	if err != nil {
+		diags.AddAttributeError(
+			attributePath.AtName(pemAttributeName(c.knownHosts, "known_hosts", "known_hosts_file")),
+			"Invalid SSH Known Hosts",
+			"The known hosts are used to verify the key of the bastion host.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return nil, diags
+	}
+
+	port := c.port
+	if port == 0 {
+		port = defaultSshPort
+	}
+
+	return &sshTunnel{
+		address: net.JoinHostPort(c.host, strconv.FormatInt(port, 10)),
+		config: &ssh.ClientConfig{
+			User:            c.user,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+		},
+	}, diags
+}
+
+// hostKeyCallback verifies the key of the bastion host against the known hosts, read
+// from ~/.ssh/known_hosts unless set.
+func (c *clientSshTunnelConfig) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if c.knownHosts == "" {
+		file := c.knownHostsFile
+		if file == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			file = filepath.Join(home, ".ssh", "known_hosts")
+		}
+		return knownhosts.New(file)
+	}
+
+	// The known hosts package only reads files.
+	file, err := os.CreateTemp("", "known_hosts")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+	if _, err := file.WriteString(c.knownHosts); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	return knownhosts.New(file.Name())
+}
+
+// pemAttributeName returns the name of the attribute holding a value given inline or as a file.
+func pemAttributeName(inline string, inlineAttribute string, fileAttribute string) string {
+	if inline != "" {
+		return inlineAttribute
+	}
+	return fileAttribute
+}
+
+// sshTunnel is a dialer opening the connections of the client through an SSH connection
+// to a bastion host, which is opened again when it is lost.
+type sshTunnel struct {
+	address string
+	config  *ssh.ClientConfig
+
+	mu     sync.Mutex
+	client *ssh.Client
+	closed bool
+}
+
+var errSshTunnelClosed = errors.New("the SSH tunnel is closed")
+
+// DialContext opens a connection to address from the bastion host, so that the hosts
+// of the deployment are resolved and reached from there.
+func (t *sshTunnel) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	client, err := t.connect(ctx)
+	if err != nil {
+		return nil, &sshTunnelError{err: err}
+	}
+	return client.DialContext(ctx, network, address)
+}
+
+func (t *sshTunnel) connect(ctx context.Context) (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, errSshTunnelClosed
+	}
+	if t.client != nil {
+		return t.client, nil
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.address)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(sshHandshakeTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, t.address, t.config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	client := ssh.NewClient(sshConn, channels, requests)
+	t.client = client
+
+	// Forget the connection once it is lost, so that the next dial opens it again.
+	go func() {
+		_ = client.Wait()
+		t.mu.Lock()
+		if t.client == client {
+			t.client = nil
+		}
+		t.mu.Unlock()
+	}()
+
+	return client, nil
+}
+
+// Close closes the SSH connection to the bastion host.
+func (t *sshTunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.closed = true
+	if t.client == nil {
+		return nil
+	}
+	err := t.client.Close()
+	t.client = nil
+	return err
+}
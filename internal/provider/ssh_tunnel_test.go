@@ -0,0 +1,258 @@
+package provider
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// testSshServer is an in-process SSH server forwarding the direct-tcpip channels, as a
+// bastion host does.
+type testSshServer struct {
+	address    string
+	hostKey    ssh.PublicKey
+	clientKey  string
+	knownHosts string
+}
+
+// newTestSshKey returns a new ed25519 key along with its PEM encoding.
+func newTestSshKey(t *testing.T) (ssh.Signer, string) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return signer, string(pem.EncodeToMemory(block))
+}
+
+// newTestSshServer starts an SSH server accepting the returned client key, stopped at the end of the test.
+func newTestSshServer(t *testing.T) *testSshServer {
+	t.Helper()
+
+	hostSigner, _ := newTestSshKey(t)
+	clientSigner, clientKey := newTestSshKey(t)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(clientSigner.PublicKey().Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSshConn(conn, config)
+		}
+	}()
+
+	address := listener.Addr().String()
+	return &testSshServer{
+		address:    address,
+		hostKey:    hostSigner.PublicKey(),
+		clientKey:  clientKey,
+		knownHosts: knownhosts.Line([]string{knownhosts.Normalize(address)}, hostSigner.PublicKey()) + "\n",
+	}
+}
+
+func serveTestSshConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		var target struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		targetConn, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.FormatUint(uint64(target.Port), 10)))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			_ = targetConn.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelRequests)
+		go func() {
+			_, _ = io.Copy(channel, targetConn)
+			_ = channel.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(targetConn, channel)
+			_ = targetConn.Close()
+		}()
+	}
+}
+
+// tunnelConfig returns the settings of a tunnel through the server.
+func (s *testSshServer) tunnelConfig(t *testing.T) *clientSshTunnelConfig {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(s.address)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	portNumber, err := strconv.ParseInt(port, 10, 64)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return &clientSshTunnelConfig{
+		host:       host,
+		port:       portNumber,
+		user:       "terraform",
+		privateKey: s.clientKey,
+		knownHosts: s.knownHosts,
+	}
+}
+
+// newTestEchoServer starts a TCP server echoing what it receives.
+func newTestEchoServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _ = io.Copy(conn, conn)
+				_ = conn.Close()
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestSshTunnelDial(t *testing.T) {
+	server := newTestSshServer(t)
+	echoAddress := newTestEchoServer(t)
+
+	tunnel, diags := server.tunnelConfig(t).dialer()
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	defer func() { _ = tunnel.Close() }()
+
+	conn, err := tunnel.DialContext(context.Background(), "tcp", echoAddress)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	received := make([]byte, 5)
+	if _, err := io.ReadFull(conn, received); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(received) != "hello" {
+		t.Fatalf("Expected hello, got %v", string(received))
+	}
+}
+
+func TestSshTunnelUnknownHostKey(t *testing.T) {
+	server := newTestSshServer(t)
+	other := newTestSshServer(t)
+
+	config := server.tunnelConfig(t)
+	config.knownHosts = knownhosts.Line([]string{knownhosts.Normalize(server.address)}, other.hostKey) + "\n"
+	tunnel, diags := config.dialer()
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	defer func() { _ = tunnel.Close() }()
+
+	_, err := tunnel.DialContext(context.Background(), "tcp", newTestEchoServer(t))
+	if err == nil {
+		t.Fatalf("Should have failed")
+	}
+	if classifyConnectionError(err) != sshTunnelFailure {
+		t.Fatalf("Expected an SSH tunnel failure, got %v", err)
+	}
+}
+
+func TestSshTunnelClosed(t *testing.T) {
+	server := newTestSshServer(t)
+
+	tunnel, diags := server.tunnelConfig(t).dialer()
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if err := tunnel.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := tunnel.DialContext(context.Background(), "tcp", newTestEchoServer(t))
+	if !errors.Is(err, errSshTunnelClosed) {
+		t.Fatalf("Expected %v, got %v", errSshTunnelClosed, err)
+	}
+}
+
+func TestClientOptionsSshTunnelInvalidKey(t *testing.T) {
+	config := clientConfig{
+		url: "mongodb://localhost",
+		sshTunnel: &clientSshTunnelConfig{
+			host:       "bastion.example.com",
+			user:       "terraform",
+			privateKey: "not a key",
+		},
+	}
+
+	_, diags := config.clientOptions()
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// Type of the keys of a text index.
+	textIndexType = "text"
+	// Keys replacing the text keys in the keys listed by the server.
+	textIndexFtsKey  = "_fts"
+	textIndexFtsxKey = "_ftsx"
+
+	// Options the server sets on text indexes when they are not given.
+	defaultTextIndexLanguage         = "english"
+	defaultTextIndexLanguageOverride = "language"
+	defaultTextIndexWeight           = 1
+	defaultTextIndexVersion          = 3
+)
+
+// textIndexOptions holds the options of a text index, as listed by the server.
+type textIndexOptions struct {
+	Weights          bson.D  `bson:"weights"`
+	DefaultLanguage  *string `bson:"default_language"`
+	LanguageOverride *string `bson:"language_override"`
+	TextIndexVersion *int32  `bson:"textIndexVersion"`
+}
+
+// textIndexConfig holds the attributes of the configuration checked by validate, which
+// may not be known yet.
+type textIndexConfig struct {
+	Keys             types.List
+	Weights          types.Map
+	DefaultLanguage  types.String
+	LanguageOverride types.String
+	TextIndexVersion types.Int64
+}
+
+// indexKeyConfig is a key of the configuration, whose values may not be known yet.
+type indexKeyConfig struct {
+	Field types.String `tfsdk:"field"`
+	Type  types.String `tfsdk:"type"`
+}
+
+// validate checks that the text options are only set on text indexes, and that the
+// weights are given to text keys. The server would otherwise index the weighted fields
+// as text keys, which would not match the keys of the resource. The values that are not
+// known yet are skipped.
+func (c *textIndexConfig) validate(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if c.Keys.IsNull() || c.Keys.IsUnknown() {
+		return diags
+	}
+	for _, element := range c.Keys.Elements() {
+		if element.IsUnknown() {
+			return diags
+		}
+	}
+	var keys []indexKeyConfig
+	diags.Append(c.Keys.ElementsAs(ctx, &keys, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	var textFields []string
+	textFieldsKnown := true
+	for _, key := range keys {
+		if key.Type.IsUnknown() {
+			return diags
+		}
+		if key.Type.ValueString() != textIndexType {
+			continue
+		}
+		if key.Field.IsUnknown() {
+			textFieldsKnown = false
+		}
+		textFields = append(textFields, key.Field.ValueString())
+	}
+
+	if len(textFields) == 0 {
+		options := []struct {
+			name  string
+			value attr.Value
+		}{
+			{"weights", c.Weights},
+			{"default_language", c.DefaultLanguage},
+			{"language_override", c.LanguageOverride},
+			{"text_index_version", c.TextIndexVersion},
+		}
+		for _, option := range options {
+			if !option.value.IsNull() && !option.value.IsUnknown() {
+				diags.AddAttributeError(
+					path.Root(option.name),
+					"Invalid Text Index Option",
+					"The "+option.name+" can only be set on text indexes, which have at least one key of type text.",
+				)
+			}
+		}
+		return diags
+	}
+
+	if textFieldsKnown && !c.Weights.IsNull() && !c.Weights.IsUnknown() {
+		for field := range c.Weights.Elements() {
+			if !slices.Contains(textFields, field) {
+				diags.AddAttributeError(
+					path.Root("weights").AtMapKey(field),
+					"Invalid Text Index Weight",
+					"The weights can only be given to the keys of type text, "+field+" is not one of them.",
+				)
+			}
+		}
+	}
+	return diags
+}
+
+// textIndexWeights returns the weights of the text index, sorted by field so that the
+// commands are the same on every run.
+func (m *indexResourceModel) textIndexWeights() bson.D {
+	if m.Weights == nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(*m.Weights))
+	for field := range *m.Weights {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	weights := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		weights = append(weights, bson.E{Key: field, Value: (*m.Weights)[field]})
+	}
+	return weights
+}
+
+// readTextIndex sets the keys and text options of the state from a text index listed by
+// the server.
+func readTextIndex(rawIndex bson.Raw, serverKeys bson.D, declared *indexResourceModel, state *indexResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var options textIndexOptions
+	err := bson.Unmarshal(rawIndex, &options)
+	if err == nil {
+		state.Keys, err = readTextIndexKeys(serverKeys, options, declared.Keys)
+	}
+	if err == nil {
+		state.Weights, err = readTextIndexWeights(options, declared.Weights)
+	}
+	if err != nil {
+		diags.AddError(
+			"Unable to parse text index",
+			"An unexpected error occurred when parsing the keys and options of the text index. "+
+				"If the error is not clear, please contact the provider developers.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return diags
+	}
+
+	state.DefaultLanguage = readIndexOption(options.DefaultLanguage, declared.DefaultLanguage, defaultTextIndexLanguage)
+	state.LanguageOverride = readIndexOption(options.LanguageOverride, declared.LanguageOverride, defaultTextIndexLanguageOverride)
+	state.TextIndexVersion = readIndexOption(options.TextIndexVersion, declared.TextIndexVersion, defaultTextIndexVersion)
+	return diags
+}
+
+// readTextIndexKeys converts the keys listed by the server for a text index back into the
+// keys of the resource. The server replaces the text keys by the _fts and _ftsx keys, the
+// text fields being the ones of the weights. They are listed in the order of the declared
+// keys, the fields unknown to the resource, such as on import, coming last.
+func readTextIndexKeys(serverKeys bson.D, options textIndexOptions, declared []indexKey) ([]indexKey, error) {
+	var textFields []string
+	for _, key := range declared {
+		if key.Type == textIndexType && hasWeight(options.Weights, key.Field) {
+			textFields = append(textFields, key.Field)
+		}
+	}
+	for _, weight := range options.Weights {
+		if !slices.Contains(textFields, weight.Key) {
+			textFields = append(textFields, weight.Key)
+		}
+	}
+	if len(textFields) == 0 {
+		return nil, errors.New("the text index has no weights")
+	}
+
+	keys := make([]indexKey, 0, len(serverKeys)+len(textFields))
+	for _, key := range serverKeys {
+		switch key.Key {
+		case textIndexFtsKey:
+			for _, field := range textFields {
+				keys = append(keys, indexKey{Field: field, Type: textIndexType})
+			}
+		case textIndexFtsxKey:
+		default:
+			typ, err := convertToTfIndexType(key.Value)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, indexKey{Field: key.Key, Type: typ})
+		}
+	}
+	return keys, nil
+}
+
+// readTextIndexWeights returns the weights of the resource from the ones listed by the
+// server. The server lists a weight for every text field, only the declared ones and
+// the ones differing from the default are kept.
+func readTextIndexWeights(options textIndexOptions, declared *map[string]int32) (*map[string]int32, error) {
+	weights := map[string]int32{}
+	for _, weight := range options.Weights {
+		value, ok := int32Value(weight.Value)
+		if !ok {
+			return nil, fmt.Errorf("unexpected weight %v of the field %s", weight.Value, weight.Key)
+		}
+
+		isDeclared := false
+		if declared != nil {
+			_, isDeclared = (*declared)[weight.Key]
+		}
+		if isDeclared || value != defaultTextIndexWeight {
+			weights[weight.Key] = value
+		}
+	}
+
+	if len(weights) == 0 && declared == nil {
+		return nil, nil
+	}
+	return &weights, nil
+}
+
+func hasWeight(weights bson.D, field string) bool {
+	for _, weight := range weights {
+		if weight.Key == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReadTextIndexKeysInDeclaredOrder(t *testing.T) {
+	serverKeys := bson.D{{Key: "category", Value: int32(1)}, {Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}, {Key: "date", Value: int32(-1)}}
+	options := textIndexOptions{Weights: bson.D{{Key: "body", Value: int32(1)}, {Key: "title", Value: int32(10)}}}
+	declared := []indexKey{{"category", "asc"}, {"title", "text"}, {"body", "text"}, {"date", "desc"}}
+
+	keys, err := readTextIndexKeys(serverKeys, options, declared)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(keys, declared) {
+		t.Fatalf("Expected %v, got %v", declared, keys)
+	}
+}
+
+func TestReadTextIndexKeysOnImport(t *testing.T) {
+	serverKeys := bson.D{{Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}}
+	options := textIndexOptions{Weights: bson.D{{Key: "body", Value: int32(1)}, {Key: "title", Value: int32(1)}}}
+
+	keys, err := readTextIndexKeys(serverKeys, options, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []indexKey{{"body", "text"}, {"title", "text"}}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("Expected %v, got %v", want, keys)
+	}
+}
+
+func TestReadTextIndexKeysWithoutWeights(t *testing.T) {
+	serverKeys := bson.D{{Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}}
+
+	if _, err := readTextIndexKeys(serverKeys, textIndexOptions{}, nil); err == nil {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestReadTextIndexWeights(t *testing.T) {
+	options := textIndexOptions{Weights: bson.D{
+		{Key: "body", Value: int32(1)},
+		{Key: "summary", Value: float64(5)},
+		{Key: "title", Value: int32(1)},
+	}}
+
+	weights, err := readTextIndexWeights(options, &map[string]int32{"title": 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]int32{"summary": 5, "title": 1}
+	if !reflect.DeepEqual(*weights, want) {
+		t.Fatalf("Expected %v, got %v", want, *weights)
+	}
+
+	weights, err = readTextIndexWeights(textIndexOptions{Weights: bson.D{{Key: "body", Value: int32(1)}}}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if weights != nil {
+		t.Fatalf("Expected the default weights to be left unset, got %v", *weights)
+	}
+
+	if _, err := readTextIndexWeights(textIndexOptions{Weights: bson.D{{Key: "body", Value: 1.5}}}, nil); err == nil {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestValidateTextIndex(t *testing.T) {
+	ctx := context.Background()
+	config := textIndexConfig{
+		Keys:             indexKeysConfig(indexKeyConfig{types.StringValue("title"), types.StringValue("text")}, indexKeyConfig{types.StringValue("body"), types.StringValue("text")}),
+		Weights:          types.MapValueMust(types.Int64Type, map[string]attr.Value{"title": types.Int64Value(10)}),
+		DefaultLanguage:  types.StringValue("french"),
+		LanguageOverride: types.StringNull(),
+		TextIndexVersion: types.Int64Null(),
+	}
+	if diags := config.validate(ctx); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	config.Weights = types.MapValueMust(types.Int64Type, map[string]attr.Value{"summary": types.Int64Value(10)})
+	if diags := config.validate(ctx); !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+
+	config.Keys = indexKeysConfig(indexKeyConfig{types.StringValue("title"), types.StringValue("asc")})
+	config.Weights = types.MapNull(types.Int64Type)
+	if diags := config.validate(ctx); !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestValidateTextIndexSkipsUnknownValues(t *testing.T) {
+	ctx := context.Background()
+	config := textIndexConfig{
+		Keys:             indexKeysConfig(indexKeyConfig{types.StringUnknown(), types.StringValue("text")}),
+		Weights:          types.MapValueMust(types.Int64Type, map[string]attr.Value{"title": types.Int64Value(10)}),
+		DefaultLanguage:  types.StringNull(),
+		LanguageOverride: types.StringNull(),
+		TextIndexVersion: types.Int64Null(),
+	}
+	if diags := config.validate(ctx); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	config.Keys = types.ListUnknown(config.Keys.ElementType(ctx))
+	if diags := config.validate(ctx); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	config.Keys = indexKeysConfig(indexKeyConfig{types.StringValue("title"), types.StringValue("asc")})
+	config.Weights = types.MapUnknown(types.Int64Type)
+	config.DefaultLanguage = types.StringUnknown()
+	if diags := config.validate(ctx); diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+}
+
+// indexKeysConfig returns the keys attribute of a configuration.
+func indexKeysConfig(keys ...indexKeyConfig) types.List {
+	keyType := map[string]attr.Type{"field": types.StringType, "type": types.StringType}
+	elements := make([]attr.Value, 0, len(keys))
+	for _, key := range keys {
+		elements = append(elements, types.ObjectValueMust(keyType, map[string]attr.Value{"field": key.Field, "type": key.Type}))
+	}
+	return types.ListValueMust(types.ObjectType{AttrTypes: keyType}, elements)
+}
+
+func TestTextIndexWeightsSorted(t *testing.T) {
+	model := indexResourceModel{Weights: &map[string]int32{"title": 10, "body": 2}}
+
+	want := bson.D{{Key: "body", Value: int32(2)}, {Key: "title", Value: int32(10)}}
+	if weights := model.textIndexWeights(); !reflect.DeepEqual(weights, want) {
+		t.Fatalf("Expected %v, got %v", want, weights)
+	}
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// clientTlsConfig holds the TLS settings of a MongoDB client. Certificates and keys
+// are either given inline as PEM or as paths to PEM files.
+type clientTlsConfig struct {
+	caCertificatePem      string
+	caCertificateFile     string
+	clientCertificatePem  string
+	clientCertificateFile string
+	clientKeyPem          string
+	clientKeyFile         string
+	serverName            string
+	insecureSkipVerify    bool
+}
+
+// tlsConfig builds the TLS configuration given to the MongoDB driver.
+func (c *clientTlsConfig) tlsConfig() (*tls.Config, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.serverName,
+		InsecureSkipVerify: c.insecureSkipVerify,
+	}
+
+	caCertificate, caDiags := readPem(c.caCertificatePem, c.caCertificateFile, path.Root("tls").AtName("ca_certificate_file"))
+	diags.Append(caDiags...)
+	if caCertificate != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCertificate) {
+			diags.AddAttributeError(
+				c.attributePath("ca_certificate_pem", "ca_certificate_file", c.caCertificateFile),
+				"Invalid CA Certificate",
+				"The CA certificate does not contain any valid PEM encoded certificate.",
+			)
+		}
+		config.RootCAs = pool
+	}
+
+	clientCertificate, certificateDiags := readPem(c.clientCertificatePem, c.clientCertificateFile, path.Root("tls").AtName("client_certificate_file"))
+	diags.Append(certificateDiags...)
+	clientKey, keyDiags := readPem(c.clientKeyPem, c.clientKeyFile, path.Root("tls").AtName("client_key_file"))
+	diags.Append(keyDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if clientKey != nil && clientCertificate == nil {
+		diags.AddAttributeError(
+			c.attributePath("client_key_pem", "client_key_file", c.clientKeyFile),
+			"Missing Client Certificate",
+			"A client key is set without a client certificate. "+
+				"Set either client_certificate_pem or client_certificate_file.",
+		)
+		return nil, diags
+	}
+
+	if clientCertificate != nil {
+		// Without a dedicated key, the key is expected to be bundled with the
+		// certificate, as in the tlsCertificateKeyFile option of the url.
+		if clientKey == nil {
+			clientKey = clientCertificate
+		}
+		certificate, err := tls.X509KeyPair(clientCertificate, clientKey)
+		if err != nil {
+			diags.AddAttributeError(
+				c.attributePath("client_certificate_pem", "client_certificate_file", c.clientCertificateFile),
+				"Invalid Client Certificate",
+				"The client certificate and key could not be loaded.\n\n"+
+					"Error: "+err.Error(),
+			)
+			return nil, diags
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, diags
+}
+
+// attributePath returns the path of the PEM attribute, or of the file attribute
+// when the value was read from a file.
+func (c *clientTlsConfig) attributePath(pemAttribute string, fileAttribute string, file string) path.Path {
+	if file != "" {
+		return path.Root("tls").AtName(fileAttribute)
+	}
+	return path.Root("tls").AtName(pemAttribute)
+}
+
+// readPem returns the inline PEM content or reads it from file, nil when neither is set.
+func readPem(pem string, file string, fileAttributePath path.Path) ([]byte, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if pem != "" {
+		return []byte(pem), diags
+	}
+	if file == "" {
+		return nil, diags
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		diags.AddAttributeError(
+			fileAttributePath,
+			"Unable to Read PEM File",
+			"The file "+file+" could not be read.\n\n"+
+				"Error: "+err.Error(),
+		)
+		return nil, diags
+	}
+	return content, diags
+}
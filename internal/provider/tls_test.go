@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	certPem     string
+	keyPem      string
+}
+
+// newTestCertificate creates a certificate signed by parent, or a self-signed CA when parent is nil.
+func newTestCertificate(t *testing.T, subject pkix.Name, parent *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Unable to create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Unable to parse certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Unable to marshal key: %v", err)
+	}
+
+	return &testCertificate{
+		certificate: certificate,
+		key:         key,
+		certPem:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPem:      string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})),
+	}
+}
+
+func TestTlsConfigFromPem(t *testing.T) {
+	ca := newTestCertificate(t, pkix.Name{CommonName: "ca"}, nil)
+	client := newTestCertificate(t, pkix.Name{CommonName: "client"}, ca)
+
+	config := clientTlsConfig{
+		caCertificatePem:     ca.certPem,
+		clientCertificatePem: client.certPem,
+		clientKeyPem:         client.keyPem,
+		serverName:           "mongo.local",
+	}
+
+	tlsConfig, diags := config.tlsConfig()
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if tlsConfig.RootCAs == nil || len(tlsConfig.Certificates) != 1 || tlsConfig.ServerName != "mongo.local" {
+		t.Fatalf("Unexpected TLS configuration %+v", tlsConfig)
+	}
+}
+
+func TestTlsConfigFromFiles(t *testing.T) {
+	ca := newTestCertificate(t, pkix.Name{CommonName: "ca"}, nil)
+	client := newTestCertificate(t, pkix.Name{CommonName: "client"}, ca)
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	clientFile := filepath.Join(dir, "client.pem")
+	if err := os.WriteFile(caFile, []byte(ca.certPem), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// the client key is bundled with the certificate
+	if err := os.WriteFile(clientFile, []byte(client.certPem+client.keyPem), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := clientTlsConfig{
+		caCertificateFile:     caFile,
+		clientCertificateFile: clientFile,
+	}
+
+	tlsConfig, diags := config.tlsConfig()
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+	if tlsConfig.RootCAs == nil || len(tlsConfig.Certificates) != 1 {
+		t.Fatalf("Unexpected TLS configuration %+v", tlsConfig)
+	}
+}
+
+func TestTlsConfigMissingFile(t *testing.T) {
+	config := clientTlsConfig{caCertificateFile: filepath.Join(t.TempDir(), "missing.pem")}
+
+	_, diags := config.tlsConfig()
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestTlsConfigInvalidCa(t *testing.T) {
+	config := clientTlsConfig{caCertificatePem: "not a certificate"}
+
+	_, diags := config.tlsConfig()
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestTlsConfigKeyWithoutCertificate(t *testing.T) {
+	ca := newTestCertificate(t, pkix.Name{CommonName: "ca"}, nil)
+
+	config := clientTlsConfig{clientKeyPem: ca.keyPem}
+
+	_, diags := config.tlsConfig()
+	if !diags.HasError() {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestTlsConfigMutualHandshake(t *testing.T) {
+	ca := newTestCertificate(t, pkix.Name{CommonName: "ca"}, nil)
+	server := newTestCertificate(t, pkix.Name{CommonName: "localhost"}, ca)
+	client := newTestCertificate(t, pkix.Name{CommonName: "client"}, ca)
+
+	serverCertificate, err := tls.X509KeyPair([]byte(server.certPem), []byte(server.keyPem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.certificate)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{serverCertificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if tlsConn, ok := conn.(*tls.Conn); ok {
+			_ = tlsConn.Handshake()
+		}
+	}()
+
+	config := clientTlsConfig{
+		caCertificatePem:     ca.certPem,
+		clientCertificatePem: client.certPem,
+		clientKeyPem:         client.keyPem,
+		serverName:           "localhost",
+	}
+	tlsConfig, diags := config.tlsConfig()
+	if diags.HasError() {
+		t.Fatalf("Unexpected error: %v", diags)
+	}
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), tlsConfig)
+	if err != nil {
+		t.Fatalf("Handshake failed: %v", err)
+	}
+	conn.Close()
+}
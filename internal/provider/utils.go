@@ -1,8 +1,15 @@
 package provider
 
 import (
+	"context"
 	"errors"
+	"os"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -42,18 +49,24 @@ func convertToTfIndexType(indexType interface{}) (string, error) {
 }
 
 type indexId struct {
+	cluster    string
 	database   string
 	collection string
 	indexName  string
 }
 
 func parseIndexId(path string) (*indexId, error) {
-	splitPath := strings.Split(path, ".")
+	cluster, namespace, found := strings.Cut(path, ":")
+	if !found {
+		cluster, namespace = "", path
+	}
+
+	splitPath := strings.Split(namespace, ".")
 	if len(splitPath) != 3 {
-		return nil, errors.New("Index id's format must be <database>.<collection>.<index_name>")
+		return nil, errors.New("Index id's format must be [<cluster>:]<database>.<collection>.<index_name>")
 	}
 
-	return &indexId{database: splitPath[0], collection: splitPath[1], indexName: splitPath[2]}, nil
+	return &indexId{cluster: cluster, database: splitPath[0], collection: splitPath[1], indexName: splitPath[2]}, nil
 }
 
 func (co *collation) toMongoCollation() *options.Collation {
@@ -90,3 +103,108 @@ func (co *collation) toMongoCollation() *options.Collation {
 	}
 	return &res
 }
+
+// readIndexOption returns the value of an index option listed by the server when it is
+// declared by the resource or differs from the default.
+func readIndexOption[T comparable](value *T, declared *T, defaultValue T) *T {
+	if value == nil || (declared == nil && *value == defaultValue) {
+		return nil
+	}
+	return value
+}
+
+// int32Value converts a number listed by the server, which may be stored as any numeric type.
+func int32Value(value interface{}) (int32, bool) {
+	switch v := value.(type) {
+	case int32:
+		return v, true
+	case int64:
+		return int32(v), true
+	case float64:
+		return int32(v), float64(int32(v)) == v
+	default:
+		return 0, false
+	}
+}
+
+// Get the value of an optional string, empty when it is not set.
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// Get the value of an optional boolean, false when it is not set.
+func boolValue(value *bool) bool {
+	return value != nil && *value
+}
+
+// Get the value of a string attribute, falling back to an environment variable when the attribute is not set.
+func stringValueOrEnv(value types.String, envVar string) string {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueString()
+	}
+	return os.Getenv(envVar)
+}
+
+// Get the value of a duration attribute, falling back to an environment variable when the attribute is not set.
+func durationValueOrEnv(value types.String, envVar string) (*time.Duration, error) {
+	str := stringValueOrEnv(value, envVar)
+	if str == "" {
+		return nil, nil
+	}
+	duration, err := parseDuration(str)
+	if err != nil {
+		return nil, err
+	}
+	return &duration, nil
+}
+
+// Get the value of an integer attribute, falling back to an environment variable when the attribute is not set.
+func int64ValueOrEnv(value types.Int64, envVar string) (*int64, error) {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueInt64Pointer(), nil
+	}
+	str := os.Getenv(envVar)
+	if str == "" {
+		return nil, nil
+	}
+	integer, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &integer, nil
+}
+
+// Get the value of a boolean attribute, falling back to an environment variable when the attribute is not set.
+func boolValueOrEnv(value types.Bool, envVar string) (*bool, error) {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueBoolPointer(), nil
+	}
+	str := os.Getenv(envVar)
+	if str == "" {
+		return nil, nil
+	}
+	boolean, err := strconv.ParseBool(str)
+	if err != nil {
+		return nil, err
+	}
+	return &boolean, nil
+}
+
+// Get the values of a list of strings attribute, falling back to a comma separated environment variable when
+// the attribute is not set.
+func stringListValueOrEnv(ctx context.Context, value types.List, envVar string) ([]string, diag.Diagnostics) {
+	var values []string
+	if !value.IsNull() && !value.IsUnknown() {
+		diags := value.ElementsAs(ctx, &values, false)
+		return values, diags
+	}
+	for _, str := range strings.Split(os.Getenv(envVar), ",") {
+		if str = strings.TrimSpace(str); str != "" {
+			values = append(values, str)
+		}
+	}
+	return values, nil
+}
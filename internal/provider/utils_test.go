@@ -1,6 +1,12 @@
 package provider
 
-import "testing"
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 func TestConvertToMongoIndexTypeAsc(t *testing.T) {
 	val := convertToMongoIndexType("asc")
@@ -71,6 +77,21 @@ func TestParseValidIndexId(t *testing.T) {
 	}
 }
 
+func TestParseIndexIdWithCluster(t *testing.T) {
+	id, err := parseIndexId("eu:db.collec.index_name")
+
+	want := indexId{
+		cluster:    "eu",
+		database:   "db",
+		collection: "collec",
+		indexName:  "index_name",
+	}
+
+	if err != nil || want != *id {
+		t.Fatalf("Expected %v, got %v, err %v", want, id, err)
+	}
+}
+
 func TestParseInvalidIndex(t *testing.T) {
 	_, err := parseIndexId("db.collec")
 
@@ -78,3 +99,69 @@ func TestParseInvalidIndex(t *testing.T) {
 		t.Fatalf("Should have failed")
 	}
 }
+
+func TestDurationValueOrEnvFromAttribute(t *testing.T) {
+	t.Setenv("MONGODB_TEST_DURATION", "1m")
+
+	val, err := durationValueOrEnv(types.StringValue("10s"), "MONGODB_TEST_DURATION")
+	if err != nil || *val != 10*time.Second {
+		t.Fatalf("Expected 10s, got %v, err %v", val, err)
+	}
+}
+
+func TestDurationValueOrEnvFromEnv(t *testing.T) {
+	t.Setenv("MONGODB_TEST_DURATION", "1m")
+
+	val, err := durationValueOrEnv(types.StringNull(), "MONGODB_TEST_DURATION")
+	if err != nil || *val != time.Minute {
+		t.Fatalf("Expected 1m, got %v, err %v", val, err)
+	}
+}
+
+func TestDurationValueOrEnvInvalidEnv(t *testing.T) {
+	t.Setenv("MONGODB_TEST_DURATION", "-1m")
+
+	_, err := durationValueOrEnv(types.StringNull(), "MONGODB_TEST_DURATION")
+	if err == nil {
+		t.Fatalf("Should have failed")
+	}
+}
+
+func TestInt64ValueOrEnvUnset(t *testing.T) {
+	val, err := int64ValueOrEnv(types.Int64Null(), "MONGODB_TEST_UNSET")
+	if err != nil || val != nil {
+		t.Fatalf("Expected nil, got %v, err %v", val, err)
+	}
+}
+
+func TestBoolValueOrEnvFromEnv(t *testing.T) {
+	t.Setenv("MONGODB_TEST_BOOL", "false")
+
+	val, err := boolValueOrEnv(types.BoolNull(), "MONGODB_TEST_BOOL")
+	if err != nil || *val {
+		t.Fatalf("Expected false, got %v, err %v", val, err)
+	}
+}
+
+func TestStringListValueOrEnvFromEnv(t *testing.T) {
+	t.Setenv("MONGODB_TEST_LIST", "zstd, snappy")
+
+	val, diags := stringListValueOrEnv(context.Background(), types.ListNull(types.StringType), "MONGODB_TEST_LIST")
+	if diags.HasError() || len(val) != 2 || val[0] != "zstd" || val[1] != "snappy" {
+		t.Fatalf("Expected [zstd snappy], got %v, diags %v", val, diags)
+	}
+}
+
+func TestReadIndexOption(t *testing.T) {
+	english, french := "english", "french"
+
+	if value := readIndexOption(&english, nil, defaultTextIndexLanguage); value != nil {
+		t.Fatalf("Expected the default language to be left unset, got %v", *value)
+	}
+	if value := readIndexOption(&english, &english, defaultTextIndexLanguage); value == nil || *value != english {
+		t.Fatalf("Expected %v, got %v", english, value)
+	}
+	if value := readIndexOption(&french, nil, defaultTextIndexLanguage); value == nil || *value != french {
+		t.Fatalf("Expected %v, got %v", french, value)
+	}
+}
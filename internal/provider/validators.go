@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = durationValidator{}
+
+var errNotPositiveDuration = errors.New("duration must be positive")
+
+// durationValidator validates that a string is a positive duration, as understood by time.ParseDuration.
+type durationValidator struct{}
+
+// isDuration returns a validator checking that a string is a positive duration such as 10s or 1m30s.
+func isDuration() validator.String {
+	return durationValidator{}
+}
+
+func (v durationValidator) Description(_ context.Context) string {
+	return "value must be a positive duration such as 10s or 1m30s"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := parseDuration(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			"The "+v.Description(ctx)+", got "+req.ConfigValue.String()+".",
+		)
+	}
+}
+
+// parseDuration parses a positive duration.
+func parseDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, errNotPositiveDuration
+	}
+	return duration, nil
+}
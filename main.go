@@ -42,6 +42,11 @@ func main() {
 
 	err := providerserver.Serve(context.Background(), provider.New(version), opts)
 
+	// Close the connection pools once Terraform is done with the provider.
+	if disconnectErr := provider.DisconnectClients(context.Background()); disconnectErr != nil {
+		log.Printf("[WARN] Unable to disconnect the MongoDB clients: %s", disconnectErr)
+	}
+
 	if err != nil {
 		log.Fatal(err.Error())
 	}